@@ -4,29 +4,51 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
-	Admin    AdminConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Password  PasswordConfig
+	Server    ServerConfig
+	Admin     AdminConfig
+	Event     EventConfig
+	RateLimit RateLimitConfig
+	Ticket    TicketConfig
+	Export    ExportConfig
+	Search    SearchConfig
+	Cache     CacheConfig
+	Email     EmailConfig
+	Reminder  ReminderConfig
 }
 
 type DatabaseConfig struct {
+	Driver   string // mysql or postgres
 	Host     string
 	Port     string
 	Username string
 	Password string
 	DBName   string
+	SSLMode  string // postgres only
+
+	// Optional read replica for reports and listings, sharing the primary's credentials
+	ReplicaHost string
+	ReplicaPort string
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpireHours int
+	Secret           string
+	ExpireHours      int
+	AdminExpireHours int
+	RefreshHours     int
+}
+
+type PasswordConfig struct {
+	HashAlgorithm string
 }
 
 type ServerConfig struct {
@@ -37,27 +59,115 @@ type ServerConfig struct {
 type AdminConfig struct {
 	Email    string
 	Password string
+	Name     string
+}
+
+type EventConfig struct {
+	DefaultStatus    string
+	NameMaxLength    int
+	NameBlockedWords string // comma-separated
+
+	TrendingWindowHours int
+	MaxAdvanceDays      int // 0 disables the limit on how far ahead events may be dated
+}
+
+type RateLimitConfig struct {
+	PurchaseLimit         int
+	PurchaseWindowSeconds int
+
+	// Purchase attempts per user or IP within the window that are logged as suspicious
+	SuspiciousPurchaseThreshold     int // 0 disables
+	SuspiciousPurchaseWindowSeconds int
+}
+
+type TicketConfig struct {
+	PurchaseCutoffMinutes     int
+	CancellationCutoffMinutes int
+	MaxPerPurchase            int
+	ServiceFeeEnabled         bool
+	ServiceFeePercent         float64
+	ServiceFeeFlat            float64
+	PurchaseIsolationLevel    string
+	DuplicateWindowSeconds    int
+
+	// Check-in window around the event start, 0 leaves that side open
+	CheckInOpensMinutesBefore int
+	CheckInGraceMinutes       int
+
+	CancellationFeeTiers string // "hours:percent" pairs, empty makes cancellations free
+}
+
+type ExportConfig struct {
+	BatchSize int
+}
+
+type SearchConfig struct {
+	MinQueryLength int
+}
+
+// CacheConfig holds Cache-Control max-age values for public read endpoints, 0 disables caching
+type CacheConfig struct {
+	EventListMaxAgeSeconds   int
+	EventDetailMaxAgeSeconds int
+
+	// In-memory cache of the active events listing, per instance
+	ActiveEventsEnabled    bool
+	ActiveEventsTTLSeconds int
+}
+
+type EmailConfig struct {
+	BaseURL          string // public URL used in emailed links
+	ChangeTokenHours int
+	TemplateDir      string // overrides for the embedded email templates, empty uses the defaults
+}
+
+// ReminderConfig controls the background job that reminds ticket holders of upcoming events
+type ReminderConfig struct {
+	Enabled         bool
+	WindowHours     int // remind holders of events starting within this many hours
+	IntervalMinutes int
 }
 
 var AppConfig *Config
 
+const defaultJWTSecret = "your-super-secret-jwt-key-here-change-in-production"
+
+// exampleJWTSecrets are the placeholder secrets shipped in the docs and env.example
+var exampleJWTSecrets = map[string]bool{
+	defaultJWTSecret: true,
+	"your-super-secret-jwt-key-here-change-in-production-minimum-32-characters": true,
+	"your-production-jwt-secret-key-minimum-32-characters-long-and-random":      true,
+}
+
 func LoadConfig() {
 	// Load .env file if exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	jwtExpireHours := getEnvAsInt("JWT_EXPIRE_HOURS", 24)
+
 	AppConfig = &Config{
 		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", "mysql"),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "3306"),
 			Username: getEnv("DB_USERNAME", "root"),
 			Password: getEnv("DB_PASSWORD", "password"),
 			DBName:   getEnv("DB_NAME", "ticketing_system"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			ReplicaHost: getEnv("DB_REPLICA_HOST", ""),
+			ReplicaPort: getEnv("DB_REPLICA_PORT", getEnv("DB_PORT", "3306")),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "your-super-secret-jwt-key-here-change-in-production"),
-			ExpireHours: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+			Secret:           getEnv("JWT_SECRET", defaultJWTSecret),
+			ExpireHours:      jwtExpireHours,
+			AdminExpireHours: getEnvAsInt("JWT_ADMIN_EXPIRE_HOURS", jwtExpireHours),
+			RefreshHours:     getEnvAsInt("JWT_REFRESH_HOURS", 720),
+		},
+		Password: PasswordConfig{
+			HashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
 		},
 		Server: ServerConfig{
 			Port:    getEnv("PORT", "8080"),
@@ -66,7 +176,79 @@ func LoadConfig() {
 		Admin: AdminConfig{
 			Email:    getEnv("ADMIN_EMAIL", "admin@ticketing.com"),
 			Password: getEnv("ADMIN_PASSWORD", "admin123"),
+			Name:     getEnv("ADMIN_NAME", "System Administrator"),
+		},
+		Event: EventConfig{
+			DefaultStatus:    getEnv("EVENT_DEFAULT_STATUS", "active"),
+			NameMaxLength:    getEnvAsInt("EVENT_NAME_MAX_LENGTH", 100),
+			NameBlockedWords: getEnv("EVENT_NAME_BLOCKED_WORDS", ""),
+
+			TrendingWindowHours: getEnvAsInt("EVENT_TRENDING_WINDOW_HOURS", 24),
+			MaxAdvanceDays:      getEnvAsInt("EVENT_MAX_ADVANCE_DAYS", 1825),
+		},
+		RateLimit: RateLimitConfig{
+			PurchaseLimit:         getEnvAsInt("PURCHASE_RATE_LIMIT", 10),
+			PurchaseWindowSeconds: getEnvAsInt("PURCHASE_RATE_WINDOW_SECONDS", 60),
+
+			SuspiciousPurchaseThreshold:     getEnvAsInt("SUSPICIOUS_PURCHASE_THRESHOLD", 20),
+			SuspiciousPurchaseWindowSeconds: getEnvAsInt("SUSPICIOUS_PURCHASE_WINDOW_SECONDS", 300),
 		},
+		Ticket: TicketConfig{
+			PurchaseCutoffMinutes:     getEnvAsInt("TICKET_PURCHASE_CUTOFF_MINUTES", 60),
+			CancellationCutoffMinutes: getEnvAsInt("TICKET_CANCEL_CUTOFF_MINUTES", 120),
+			MaxPerPurchase:            getEnvAsInt("MAX_TICKETS_PER_PURCHASE", 0),
+			ServiceFeeEnabled:         getEnvAsBool("SERVICE_FEE_ENABLED", false),
+			ServiceFeePercent:         getEnvAsFloat("SERVICE_FEE_PERCENT", 0),
+			ServiceFeeFlat:            getEnvAsFloat("SERVICE_FEE_FLAT", 0),
+			PurchaseIsolationLevel:    getEnv("PURCHASE_ISOLATION_LEVEL", ""),
+			DuplicateWindowSeconds:    getEnvAsInt("DUPLICATE_PURCHASE_WINDOW_SECONDS", 0),
+
+			CheckInOpensMinutesBefore: getEnvAsInt("CHECK_IN_OPENS_MINUTES_BEFORE", 0),
+			CheckInGraceMinutes:       getEnvAsInt("CHECK_IN_GRACE_MINUTES", 0),
+
+			CancellationFeeTiers: getEnv("CANCELLATION_FEE_TIERS", ""),
+		},
+		Export: ExportConfig{
+			BatchSize: getEnvAsInt("EXPORT_BATCH_SIZE", 500),
+		},
+		Search: SearchConfig{
+			MinQueryLength: getEnvAsInt("SEARCH_MIN_LENGTH", 2),
+		},
+		Email: EmailConfig{
+			BaseURL:          getEnv("APP_BASE_URL", "http://localhost:8080"),
+			ChangeTokenHours: getEnvAsInt("EMAIL_CHANGE_TOKEN_HOURS", 24),
+			TemplateDir:      getEnv("EMAIL_TEMPLATE_DIR", ""),
+		},
+		Cache: CacheConfig{
+			EventListMaxAgeSeconds:   getEnvAsInt("CACHE_EVENT_LIST_MAX_AGE_SECONDS", 30),
+			EventDetailMaxAgeSeconds: getEnvAsInt("CACHE_EVENT_DETAIL_MAX_AGE_SECONDS", 60),
+
+			ActiveEventsEnabled:    getEnvAsBool("CACHE_ACTIVE_EVENTS_ENABLED", false),
+			ActiveEventsTTLSeconds: getEnvAsInt("CACHE_ACTIVE_EVENTS_TTL_SECONDS", 5),
+		},
+		Reminder: ReminderConfig{
+			Enabled:         getEnvAsBool("REMINDER_ENABLED", false),
+			WindowHours:     getEnvAsInt("REMINDER_WINDOW_HOURS", 24),
+			IntervalMinutes: getEnvAsInt("REMINDER_INTERVAL_MINUTES", 15),
+		},
+	}
+
+	warnInsecureJWTSecret()
+}
+
+// warnInsecureJWTSecret flags release deployments still signing tokens with a
+// placeholder or short secret. Changing the secret later logs every user out,
+// so this should be caught before the first production tokens are issued.
+func warnInsecureJWTSecret() {
+	if AppConfig.Server.GinMode != "release" {
+		return
+	}
+
+	if exampleJWTSecrets[AppConfig.JWT.Secret] {
+		log.Println("WARNING: JWT_SECRET is the example value from the documentation. Anyone can forge tokens; " +
+			"set a random secret before issuing tokens, since changing it later invalidates every session")
+	} else if len(AppConfig.JWT.Secret) < 32 {
+		log.Println("WARNING: JWT_SECRET is shorter than 32 characters; use a long random secret in release mode")
 	}
 }
 
@@ -86,6 +268,83 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func (c *Config) GetJWTDuration() time.Duration {
 	return time.Duration(c.JWT.ExpireHours) * time.Hour
-} 
\ No newline at end of file
+}
+
+func (c *Config) GetJWTAdminDuration() time.Duration {
+	return time.Duration(c.JWT.AdminExpireHours) * time.Hour
+}
+
+func (c *Config) GetJWTRefreshDuration() time.Duration {
+	return time.Duration(c.JWT.RefreshHours) * time.Hour
+}
+
+func (c *Config) GetEventListCacheMaxAge() time.Duration {
+	return time.Duration(c.Cache.EventListMaxAgeSeconds) * time.Second
+}
+
+func (c *Config) GetEventDetailCacheMaxAge() time.Duration {
+	return time.Duration(c.Cache.EventDetailMaxAgeSeconds) * time.Second
+}
+
+// GetActiveEventsCacheTTL returns 0, disabling the cache, unless it is enabled
+func (c *Config) GetActiveEventsCacheTTL() time.Duration {
+	if !c.Cache.ActiveEventsEnabled {
+		return 0
+	}
+	return time.Duration(c.Cache.ActiveEventsTTLSeconds) * time.Second
+}
+
+func (c *Config) GetEmailChangeTokenTTL() time.Duration {
+	return time.Duration(c.Email.ChangeTokenHours) * time.Hour
+}
+
+func (c *Config) GetPurchaseRateWindow() time.Duration {
+	return time.Duration(c.RateLimit.PurchaseWindowSeconds) * time.Second
+}
+
+func (c *Config) GetSuspiciousPurchaseWindow() time.Duration {
+	return time.Duration(c.RateLimit.SuspiciousPurchaseWindowSeconds) * time.Second
+}
+
+func (c *Config) GetReminderWindow() time.Duration {
+	return time.Duration(c.Reminder.WindowHours) * time.Hour
+}
+
+func (c *Config) GetReminderInterval() time.Duration {
+	return time.Duration(c.Reminder.IntervalMinutes) * time.Minute
+}
+
+func (c *Config) GetEventMaxAdvance() time.Duration {
+	return time.Duration(c.Event.MaxAdvanceDays) * 24 * time.Hour
+}
+
+func (c *Config) GetEventTrendingWindow() time.Duration {
+	return time.Duration(c.Event.TrendingWindowHours) * time.Hour
+}
+
+func (c *Config) GetEventNameBlockedWords() []string {
+	if c.Event.NameBlockedWords == "" {
+		return nil
+	}
+	return strings.Split(c.Event.NameBlockedWords, ",")
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"log"
 	"ticketing-system/entity"
+	"ticketing-system/repository"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 var DB *gorm.DB
@@ -16,42 +19,151 @@ var DB *gorm.DB
 func ConnectDatabase() {
 	var err error
 	
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		AppConfig.Database.Username,
-		AppConfig.Database.Password,
-		AppConfig.Database.Host,
-		AppConfig.Database.Port,
-		AppConfig.Database.DBName,
-	)
-
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	DB, err = gorm.Open(openDialector(AppConfig.Database.Host, AppConfig.Database.Port), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Report unique index violations as gorm.ErrDuplicatedKey on either driver
+		TranslateError: true,
 	})
 
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// Reports and listings opt into the replica; everything else stays on the primary
+	if AppConfig.Database.ReplicaHost != "" {
+		replica := openDialector(AppConfig.Database.ReplicaHost, AppConfig.Database.ReplicaPort)
+		if err := DB.Use(dbresolver.Register(dbresolver.Config{
+			Replicas: []gorm.Dialector{replica},
+		}, repository.ReadReplica)); err != nil {
+			log.Fatal("Failed to configure read replica:", err)
+		}
+		log.Println("Read replica configured")
+	}
+
 	log.Println("Database connected successfully")
 }
 
 func AutoMigrate() {
+	dropUniqueEventNameIndex()
+
 	err := DB.AutoMigrate(
 		&entity.User{},
 		&entity.Event{},
 		&entity.Ticket{},
+		&entity.EventStatusChange{},
+		&entity.RefreshToken{},
 	)
 
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	if DB.Dialector.Name() == "mysql" {
+		migrateMySQLColumns()
+	}
+
+	if err := CreateLiveEventNameIndex(DB); err != nil {
+		log.Fatal("Failed to create live event name index:", err)
+	}
+
 	log.Println("Database migration completed")
 
 	// Seed admin user
 	seedAdminUser()
 }
 
+// openDialector builds the configured driver's dialector for a database server
+func openDialector(host, port string) gorm.Dialector {
+	switch AppConfig.Database.Driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			AppConfig.Database.Username,
+			AppConfig.Database.Password,
+			host,
+			port,
+			AppConfig.Database.DBName,
+		)
+		return mysql.Open(dsn)
+	case "postgres":
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			host,
+			port,
+			AppConfig.Database.Username,
+			AppConfig.Database.Password,
+			AppConfig.Database.DBName,
+			AppConfig.Database.SSLMode,
+		)
+		return postgres.Open(dsn)
+	default:
+		log.Fatalf("Unsupported database driver %q, use mysql or postgres", AppConfig.Database.Driver)
+		return nil
+	}
+}
+
+// migrateMySQLColumns applies MySQL-specific column changes that AutoMigrate misses.
+// Former enum status and role columns need no step here: AutoMigrate sees the type
+// change to varchar and converts them, keeping their values.
+func migrateMySQLColumns() {
+	// AutoMigrate ignores collation changes, so apply the case-insensitive name collation
+	// explicitly. The check keeps startup from rebuilding the table every time.
+	var collation string
+	if err := DB.Raw("SELECT collation_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = 'events' AND column_name = 'name'").
+		Scan(&collation).Error; err != nil {
+		log.Fatal("Failed to read event name collation:", err)
+	}
+	if collation == "utf8mb4_unicode_ci" {
+		return
+	}
+	if err := DB.Exec("ALTER TABLE events MODIFY name varchar(191) COLLATE utf8mb4_unicode_ci NOT NULL").Error; err != nil {
+		log.Fatal("Failed to migrate event name column:", err)
+	}
+	log.Println("Changed event name collation to utf8mb4_unicode_ci")
+}
+
+// dropUniqueEventNameIndex removes the former unique index on events.name, which kept
+// soft-deleted events' names taken. AutoMigrate then recreates it as a plain index;
+// uniqueness among live events is enforced by CreateLiveEventNameIndex.
+func dropUniqueEventNameIndex() {
+	if !DB.Migrator().HasTable(&entity.Event{}) {
+		return
+	}
+
+	indexes, err := DB.Migrator().GetIndexes(&entity.Event{})
+	if err != nil {
+		log.Fatal("Failed to read event indexes:", err)
+	}
+	for _, index := range indexes {
+		if unique, ok := index.Unique(); index.Name() == "idx_events_name" && ok && unique {
+			if err := DB.Migrator().DropIndex(&entity.Event{}, index.Name()); err != nil {
+				log.Fatal("Failed to drop unique event name index:", err)
+			}
+			log.Println("Dropped unique event name index")
+		}
+	}
+}
+
+// CreateLiveEventNameIndex makes event names unique, ignoring case, among events that
+// are not soft-deleted. The event service checks names first for a friendly error; the
+// index stops two concurrent requests from both passing that check.
+func CreateLiveEventNameIndex(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "postgres":
+		// Also serves the LOWER(name) lookups of GetByName
+		return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_events_live_name ON events (LOWER(name)) WHERE deleted_at IS NULL").Error
+	case "mysql":
+		// MySQL has no partial indexes, so index a generated column that is NULL for deleted events
+		if !db.Migrator().HasColumn(&entity.Event{}, "live_name") {
+			if err := db.Exec("ALTER TABLE events ADD COLUMN live_name varchar(191) GENERATED ALWAYS AS (IF(deleted_at IS NULL, LOWER(name), NULL)) VIRTUAL").Error; err != nil {
+				return err
+			}
+		}
+		if !db.Migrator().HasIndex(&entity.Event{}, "idx_events_live_name") {
+			return db.Exec("CREATE UNIQUE INDEX idx_events_live_name ON events (live_name)").Error
+		}
+	}
+	return nil
+}
+
 func seedAdminUser() {
 	var adminUser entity.User
 	result := DB.Where("email = ?", AppConfig.Admin.Email).First(&adminUser)
@@ -73,7 +185,7 @@ func seedAdminUser() {
 		admin := entity.User{
 			Email:    AppConfig.Admin.Email,
 			Password: hashedPassword,
-			Name:     "System Administrator",
+			Name:     AppConfig.Admin.Name,
 			Role:     entity.RoleAdmin,
 			IsActive: true,
 		}
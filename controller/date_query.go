@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const dateOnlyLayout = "2006-01-02"
+
+// dateQuery names a date query parameter and where its parsed value goes.
+// With endOfDay set, a date-only value covers the whole day.
+type dateQuery struct {
+	name     string
+	target   **time.Time
+	endOfDay bool
+}
+
+// bindDateQueries parses date query parameters as RFC3339 timestamps or
+// YYYY-MM-DD dates, naming the offending parameter when one is malformed.
+// Missing parameters leave their target nil.
+func bindDateQueries(c *gin.Context, queries ...dateQuery) error {
+	for _, query := range queries {
+		value := c.Query(query.name)
+		if value == "" {
+			*query.target = nil
+			continue
+		}
+
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			*query.target = &parsed
+			continue
+		}
+
+		parsed, err := time.Parse(dateOnlyLayout, value)
+		if err != nil {
+			return errors.New(query.name + " must be an RFC3339 timestamp (2006-01-02T15:04:05Z) or a date (2006-01-02)")
+		}
+		if query.endOfDay {
+			parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+		}
+		*query.target = &parsed
+	}
+	return nil
+}
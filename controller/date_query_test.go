@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBindDateQueries(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	stamp := time.Date(2026, 3, 14, 9, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		wantStart *time.Time
+		wantEnd   *time.Time
+		wantErr   string
+	}{
+		{name: "missing", wantStart: nil, wantEnd: nil},
+		{name: "dates", start: "2026-03-14", end: "2026-03-14", wantStart: &day, wantEnd: ptrTime(day.Add(24*time.Hour - time.Nanosecond))},
+		{name: "timestamps", start: "2026-03-14T09:30:00+02:00", end: "2026-03-14T09:30:00+02:00", wantStart: &stamp, wantEnd: &stamp},
+		{name: "bad start", start: "14/03/2026", wantErr: "start_date must be an RFC3339 timestamp (2006-01-02T15:04:05Z) or a date (2006-01-02)"},
+		{name: "bad end", start: "2026-03-14", end: "2026-02-30", wantErr: "end_date must be an RFC3339 timestamp (2006-01-02T15:04:05Z) or a date (2006-01-02)"},
+		{name: "timestamp without zone", start: "2026-03-14T09:30:00", wantErr: "start_date must be an RFC3339 timestamp (2006-01-02T15:04:05Z) or a date (2006-01-02)"},
+	}
+
+	for _, tt := range tests {
+		query := url.Values{}
+		if tt.start != "" {
+			query.Set("start_date", tt.start)
+		}
+		if tt.end != "" {
+			query.Set("end_date", tt.end)
+		}
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+query.Encode(), nil)
+
+		// Stale values must be cleared when a parameter is missing
+		stale := time.Now()
+		start, end := &stale, &stale
+		err := bindDateQueries(c,
+			dateQuery{name: "start_date", target: &start},
+			dateQuery{name: "end_date", target: &end, endOfDay: true},
+		)
+
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !sameTime(start, tt.wantStart) || !sameTime(end, tt.wantEnd) {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func sameTime(got, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Equal(*want)
+}
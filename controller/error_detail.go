@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"ticketing-system/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorDetail returns the text for a response's Error field. Client errors are
+// returned as is. Server errors can carry driver or SQL details, so they are logged
+// with the request ID and replaced by a generic message quoting that ID.
+func errorDetail(c *gin.Context, status int, err error) string {
+	if status < http.StatusInternalServerError {
+		return err.Error()
+	}
+
+	requestID := middleware.GetRequestID(c)
+	log.Printf("request %s: %s %s failed: %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+	return "internal server error, request id " + requestID
+}
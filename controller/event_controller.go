@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strconv"
 	"ticketing-system/entity"
+	"ticketing-system/middleware"
 	"ticketing-system/service"
 
 	"github.com/gin-gonic/gin"
@@ -26,14 +29,22 @@ func NewEventController(eventService service.EventService) *EventController {
 // @Param limit query int false "Items per page" default(10)
 // @Param q query string false "Search query"
 // @Param category query string false "Filter by category"
+// @Param categories query string false "Filter by any of these comma separated categories"
 // @Param status query string false "Filter by status"
+// @Param statuses query string false "Filter by any of these comma separated statuses"
 // @Param location query string false "Filter by location"
 // @Param min_price query number false "Minimum price filter"
 // @Param max_price query number false "Maximum price filter"
-// @Param start_date query string false "Start date filter (RFC3339)"
-// @Param end_date query string false "End date filter (RFC3339)"
+// @Param min_available query int false "Only events with at least this many seats left"
+// @Param start_date query string false "Start date filter (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (RFC3339 or YYYY-MM-DD)"
+// @Param updated_since query string false "Only events changed at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param include_deleted query bool false "Include soft-deleted events with deleted_at set"
+// @Param fields query string false "Comma separated fields to return, e.g. id,name"
+// @Param created_by_email query string false "Only events created by the admin with this email (Admin only)"
 // @Success 200 {object} entity.PaginatedResponse{data=[]entity.Event}
 // @Failure 400 {object} entity.Response
+// @Failure 403 {object} entity.Response
 // @Router /events [get]
 func (ec *EventController) GetAllEvents(c *gin.Context) {
 	var pagination entity.Pagination
@@ -67,33 +78,85 @@ func (ec *EventController) GetAllEvents(c *gin.Context) {
 		return
 	}
 
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+		dateQuery{name: "updated_since", target: &filter.UpdatedSince},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Admins also see drafts and private events in listings
+	filter.IncludeDrafts = middleware.IsAdmin(c)
+	filter.IncludePrivate = middleware.IsAdmin(c)
+
+	// Who created an event is only visible to admins
+	if filter.CreatedByEmail != "" && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, entity.Response{
+			Success: false,
+			Message: "Access denied: created_by_email requires admin access",
+		})
+		return
+	}
+
 	events, meta, err := ec.eventService.GetAllEvents(&pagination, &search, &filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, entity.Response{
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid status filter" ||
+			err.Error() == "invalid fields parameter" ||
+			err.Error() == "min_available must not be negative" ||
+			err.Error() == "invalid created_by_email" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve events",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
 
+	// A fields projection returns slim objects instead of full events
+	var data interface{} = events
+	if fields := filter.FieldList(); len(fields) > 0 {
+		projected, err := entity.ProjectEvents(events, fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, entity.Response{
+				Success: false,
+				Message: "Failed to retrieve events",
+				Error:   errorDetail(c, http.StatusInternalServerError, err),
+			})
+			return
+		}
+		data = projected
+	}
+
 	c.JSON(http.StatusOK, entity.PaginatedResponse{
 		Success: true,
 		Message: "Events retrieved successfully",
-		Data:    events,
+		Data:    data,
 		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
 	})
 }
 
 // GetEventByID godoc
 // @Summary Get event by ID
-// @Description Get a single event by its ID
+// @Description Get a single event by its ID. Private events require their share token unless the caller is an admin.
 // @Tags Events
 // @Accept json
 // @Produce json
 // @Param id path string true "Event ID"
+// @Param token query string false "Share token for private events"
 // @Success 200 {object} entity.Response{data=entity.Event}
 // @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
 // @Router /events/{id} [get]
 func (ec *EventController) GetEventByID(c *gin.Context) {
 	eventID := c.Param("id")
@@ -107,14 +170,122 @@ func (ec *EventController) GetEventByID(c *gin.Context) {
 
 	event, err := ec.eventService.GetEventByID(eventID)
 	if err != nil {
+		// Only a missing record is a 404; anything else is a server failure
+		statusCode := http.StatusNotFound
+		message := "Event not found"
+		if err.Error() != "record not found" {
+			statusCode = http.StatusInternalServerError
+			message = "Failed to retrieve event"
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: message,
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	// Drafts are only visible to admins, private events also to share token holders
+	hidden := event.IsDraft() ||
+		(event.IsPrivate() && (event.ShareToken == "" ||
+			subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(event.ShareToken)) != 1))
+
+	// Never let shared caches keep drafts or private events, even for token holders
+	if event.IsDraft() || event.IsPrivate() {
+		c.Header("Cache-Control", "no-store")
+	}
+
+	if hidden && !middleware.IsAdmin(c) {
 		c.JSON(http.StatusNotFound, entity.Response{
 			Success: false,
 			Message: "Event not found",
+			Error:   "record not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Event retrieved successfully",
+		Data:    event,
+	})
+}
+
+// GetEventsByIDs godoc
+// @Summary Get several events by ID
+// @Description Fetch up to 100 events in one call, returned in the order requested. Unknown ids are skipped; drafts and private events are only included for admins.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param request body entity.BatchEventsRequest true "Event IDs"
+// @Success 200 {object} entity.Response{data=[]entity.Event}
+// @Failure 400 {object} entity.Response
+// @Router /events/batch [post]
+func (ec *EventController) GetEventsByIDs(c *gin.Context) {
+	var req entity.BatchEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	events, err := ec.eventService.GetEventsByIDs(req.IDs, middleware.IsAdmin(c))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "ids are required" ||
+			err.Error() == "too many ids in one request" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve events",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Events retrieved successfully",
+		Data:    events,
+	})
+}
+
+// GetSharedEvent godoc
+// @Summary Get event by share token
+// @Description Resolve a private event share link to its event
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} entity.Response{data=entity.Event}
+// @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /events/shared/{token} [get]
+func (ec *EventController) GetSharedEvent(c *gin.Context) {
+	event, err := ec.eventService.GetEventByShareToken(c.Param("token"))
+	if err != nil {
+		// Only a missing record is a 404; anything else is a server failure
+		statusCode := http.StatusNotFound
+		message := "Event not found"
+		if err.Error() != "record not found" {
+			statusCode = http.StatusInternalServerError
+			message = "Failed to retrieve event"
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: message,
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, entity.Response{
 		Success: true,
 		Message: "Event retrieved successfully",
@@ -122,6 +293,54 @@ func (ec *EventController) GetEventByID(c *gin.Context) {
 	})
 }
 
+// RegenerateShareToken godoc
+// @Summary Regenerate event share token (Admin only)
+// @Description Issue a new share token for a private event, revoking previously shared links
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=entity.Event}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /events/{id}/share-token [post]
+func (ec *EventController) RegenerateShareToken(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	event, err := ec.eventService.RegenerateShareToken(eventID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "only private events have share tokens" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to regenerate share token",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Share token regenerated successfully",
+		Data:    event,
+	})
+}
+
 // CreateEvent godoc
 // @Summary Create new event (Admin only)
 // @Description Create a new event
@@ -137,6 +356,15 @@ func (ec *EventController) GetEventByID(c *gin.Context) {
 // @Failure 409 {object} entity.Response
 // @Router /events [post]
 func (ec *EventController) CreateEvent(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
 	var req entity.CreateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, entity.Response{
@@ -147,19 +375,31 @@ func (ec *EventController) CreateEvent(c *gin.Context) {
 		return
 	}
 
-	event, err := ec.eventService.CreateEvent(&req)
+	event, err := ec.eventService.CreateEvent(&req, adminID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "event name already exists" {
 			statusCode = http.StatusConflict
-		} else if err.Error() == "event date cannot be in the past" {
+		} else if err.Error() == "name must be at least 3 characters" ||
+			err.Error() == "name is too long" ||
+			err.Error() == "name contains invalid characters" ||
+			err.Error() == "name must contain a letter or digit" ||
+			err.Error() == "name contains a blocked word" ||
+			err.Error() == "event date cannot be in the past" ||
+			err.Error() == "event date is too far in the future" ||
+			err.Error() == "sale end time cannot be after event date" ||
+			err.Error() == "visibility must be public or private" ||
+			err.Error() == "minimum age cannot be negative" ||
+			err.Error() == "check-in grace cannot be negative" ||
+			err.Error() == "tax rate cannot be negative" ||
+			err.Error() == "capacity must be at least 1" {
 			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to create event",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -212,18 +452,32 @@ func (ec *EventController) UpdateEvent(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "event name already exists" {
 			statusCode = http.StatusConflict
-		} else if err.Error() == "cannot modify event that is not active" ||
-			err.Error() == "capacity cannot be negative" ||
+		} else if err.Error() == "name must be at least 3 characters" ||
+			err.Error() == "name is too long" ||
+			err.Error() == "name contains invalid characters" ||
+			err.Error() == "name must contain a letter or digit" ||
+			err.Error() == "name contains a blocked word" ||
+			err.Error() == "cannot modify event that is not active" ||
+			err.Error() == "visibility must be public or private" ||
+			err.Error() == "minimum age cannot be negative" ||
+			err.Error() == "check-in grace cannot be negative" ||
+			err.Error() == "tax rate cannot be negative" ||
+			err.Error() == "capacity must be at least 1" ||
 			err.Error() == "price cannot be negative" ||
 			err.Error() == "cannot reduce capacity below sold tickets" ||
-			err.Error() == "event date cannot be in the past" {
+			err.Error() == "available tickets out of range" ||
+			err.Error() == "event date cannot be in the past" ||
+			err.Error() == "event date is too far in the future" ||
+			err.Error() == "sale end time cannot be after event date" {
 			statusCode = http.StatusBadRequest
+		} else if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to update event",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -262,14 +516,16 @@ func (ec *EventController) DeleteEvent(c *gin.Context) {
 	err := ec.eventService.DeleteEvent(eventID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "cannot delete event with sold tickets" {
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot delete event with sold tickets" {
 			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to delete event",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -280,6 +536,330 @@ func (ec *EventController) DeleteEvent(c *gin.Context) {
 	})
 }
 
+// PublishEvent godoc
+// @Summary Publish draft event (Admin only)
+// @Description Move a draft event to active so it appears in public listings
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=entity.Event}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /events/{id}/publish [post]
+func (ec *EventController) PublishEvent(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	event, err := ec.eventService.PublishEvent(eventID, adminID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "only draft events can be published" ||
+			err.Error() == "event date cannot be in the past" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to publish event",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Event published successfully",
+		Data:    event,
+	})
+}
+
+// SetEventSales godoc
+// @Summary Pause or resume ticket sales (Admin only)
+// @Description Temporarily stop or restart purchases of an event without changing its status; paused events stay listed as active
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param request body entity.EventSalesRequest true "Whether sales are paused"
+// @Success 200 {object} entity.Response{data=entity.Event}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /events/{id}/sales [patch]
+func (ec *EventController) SetEventSales(c *gin.Context) {
+	var req entity.EventSalesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	event, err := ec.eventService.SetSalesPaused(c.Param("id"), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "paused is required" ||
+			err.Error() == "cannot change sales of a cancelled or completed event" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to update event sales",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	message := "Event sales resumed successfully"
+	if event.SalesPaused {
+		message = "Event sales paused successfully"
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: message,
+		Data:    event,
+	})
+}
+
+// GetDeletedEvents godoc
+// @Summary List deleted events (Admin only)
+// @Description Get paginated soft-deleted events, most recently deleted first, with their deleted_at timestamps
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entity.PaginatedResponse{data=[]entity.Event}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /events/deleted [get]
+func (ec *EventController) GetDeletedEvents(c *gin.Context) {
+	var pagination entity.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid pagination parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	events, meta, err := ec.eventService.GetDeletedEvents(&pagination)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve deleted events",
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.PaginatedResponse{
+		Success: true,
+		Message: "Deleted events retrieved successfully",
+		Data:    events,
+		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
+	})
+}
+
+// GetEventStatusHistory godoc
+// @Summary Get event status history (Admin only)
+// @Description List every status change of an event, oldest first, with the admin who made it
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=[]entity.EventStatusChange}
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /events/{id}/status-history [get]
+func (ec *EventController) GetEventStatusHistory(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	history, err := ec.eventService.GetStatusHistory(eventID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve event status history",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Event status history retrieved successfully",
+		Data:    history,
+	})
+}
+
+// BulkUpdateEventStatus godoc
+// @Summary Bulk update event status (Admin only)
+// @Description Move several events to ongoing or completed in one transaction. Each event is checked against the allowed status transitions and reported individually.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.BulkEventStatusRequest true "Event IDs and target status"
+// @Success 200 {object} entity.Response{data=[]entity.BulkEventStatusResult}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Router /events/status [patch]
+func (ec *EventController) BulkUpdateEventStatus(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	var req entity.BulkEventStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	results, err := ec.eventService.BulkUpdateStatus(&req, adminID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "ids are required" ||
+			err.Error() == "too many ids in one request" ||
+			err.Error() == "status must be ongoing or completed" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to update event statuses",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Event statuses processed",
+		Data:    results,
+	})
+}
+
+// CancelEvent godoc
+// @Summary Cancel event (Admin only)
+// @Description Cancel an event and all of its active tickets. Cancelling an already cancelled event succeeds without changes.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=entity.EventCancellationSummary}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /events/{id}/cancel [post]
+func (ec *EventController) CancelEvent(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	summary, err := ec.eventService.CancelEvent(eventID, adminID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot cancel a completed event" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to cancel event",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	message := "Event cancelled successfully"
+	if summary.AlreadyCancelled {
+		message = "Event was already cancelled"
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: message,
+		Data:    summary,
+	})
+}
+
 // GetActiveEvents godoc
 // @Summary Get active events
 // @Description Get list of active events available for booking
@@ -295,7 +875,7 @@ func (ec *EventController) GetActiveEvents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve active events",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -307,6 +887,49 @@ func (ec *EventController) GetActiveEvents(c *gin.Context) {
 	})
 }
 
+// GetTrendingEvents godoc
+// @Summary Get trending events
+// @Description Get active, upcoming events that are not sold out, ranked by tickets sold within the trending window
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of events to return (max 100)" default(10)
+// @Success 200 {object} entity.Response{data=[]entity.TrendingEvent}
+// @Failure 400 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /events/trending [get]
+func (ec *EventController) GetTrendingEvents(c *gin.Context) {
+	limit := 10
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, entity.Response{
+				Success: false,
+				Message: "Invalid limit",
+				Error:   "limit must be a positive integer",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	events, err := ec.eventService.GetTrendingEvents(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve trending events",
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Trending events retrieved successfully",
+		Data:    events,
+	})
+}
+
 // GetUpcomingEvents godoc
 // @Summary Get upcoming events
 // @Description Get list of upcoming events
@@ -329,7 +952,7 @@ func (ec *EventController) GetUpcomingEvents(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve upcoming events",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+	"ticketing-system/entity"
+	"ticketing-system/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetaController struct {
+	ticketRules service.TicketRules
+}
+
+func NewMetaController(ticketRules service.TicketRules) *MetaController {
+	return &MetaController{ticketRules: ticketRules}
+}
+
+// GetMeta godoc
+// @Summary Get server time and limits
+// @Description Get the authoritative server time along with purchase, cancellation, check-in, fee and pagination limits
+// @Tags Meta
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.Response{data=entity.ServerMeta}
+// @Router /meta [get]
+func (mc *MetaController) GetMeta(c *gin.Context) {
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Server metadata retrieved successfully",
+		Data: entity.ServerMeta{
+			ServerTime:                time.Now().UTC(),
+			PurchaseCutoffMinutes:     int(mc.ticketRules.PurchaseCutoff / time.Minute),
+			CancellationCutoffMinutes: int(mc.ticketRules.CancellationCutoff / time.Minute),
+			CheckInOpensMinutesBefore: int(mc.ticketRules.CheckInOpensBefore / time.Minute),
+			CheckInGraceMinutes:       int(mc.ticketRules.CheckInGrace / time.Minute),
+			MaxTicketsPerPurchase:     mc.ticketRules.PurchaseLimit(),
+			ServiceFeePercent:         mc.ticketRules.ServiceFeePercent,
+			ServiceFeeFlat:            mc.ticketRules.ServiceFeeFlat,
+			DefaultPageLimit:          entity.DefaultPageLimit,
+			MaxPageLimit:              entity.MaxPageLimit,
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"strconv"
+	"ticketing-system/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationLinks builds first/prev/next/last URLs for a listing from the
+// current request, keeping every other query parameter as it was sent.
+func paginationLinks(c *gin.Context, meta *entity.PaginationMeta) *entity.PaginationLinks {
+	lastPage := meta.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	pageURL := func(page int) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(meta.Limit))
+		return c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	links := &entity.PaginationLinks{
+		First: pageURL(1),
+		Last:  pageURL(lastPage),
+	}
+	if meta.CurrentPage > 1 {
+		// Requests past the end step back to the last real page
+		links.Prev = pageURL(min(meta.CurrentPage-1, lastPage))
+	}
+	if meta.CurrentPage < lastPage {
+		links.Next = pageURL(meta.CurrentPage + 1)
+	}
+
+	return links
+}
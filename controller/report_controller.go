@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"encoding/csv"
 	"net/http"
+	"strconv"
 	"ticketing-system/entity"
 	"ticketing-system/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -34,7 +37,7 @@ func (rc *ReportController) GetSummaryReport(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to generate summary report",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -81,7 +84,7 @@ func (rc *ReportController) GetEventReport(c *gin.Context) {
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to generate event report",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -91,4 +94,425 @@ func (rc *ReportController) GetEventReport(c *gin.Context) {
 		Message: "Event report generated successfully",
 		Data:    report,
 	})
-} 
\ No newline at end of file
+}
+
+// GetDetailedEventReport godoc
+// @Summary Get detailed event report (Admin only)
+// @Description Get the event report extended with gross/net revenue, refunds, ticket status breakdown and check-in rate
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=entity.DetailedEventReport}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/event/{id}/detailed [get]
+func (rc *ReportController) GetDetailedEventReport(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	report, err := rc.ticketService.GetDetailedEventReport(eventID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to generate detailed event report",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Detailed event report generated successfully",
+		Data:    report,
+	})
+}
+
+// GetEventSalesTimeline godoc
+// @Summary Get event sales timeline (Admin only)
+// @Description Get an event's paid, non-cancelled sales bucketed by hour, day or week from the event's creation until now. Buckets without sales are included with zero values; periods are in the server's local time. Timelines over 1000 buckets are rejected; use a coarser interval.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param interval query string false "Bucket size: hour, day or week" default(day)
+// @Success 200 {object} entity.Response{data=entity.EventSalesTimeline}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/event/{id}/timeline [get]
+func (rc *ReportController) GetEventSalesTimeline(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	timeline, err := rc.ticketService.GetEventSalesTimeline(eventID, c.Query("interval"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "interval must be hour, day or week" ||
+			err.Error() == "timeline has too many points, use a coarser interval" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to generate sales timeline",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Sales timeline generated successfully",
+		Data:    timeline,
+	})
+}
+
+// GetEventsImpact godoc
+// @Summary Preview the impact of cancelling or deleting events (Admin only)
+// @Description Read-only summary per event of the active tickets, seats and refund amount a cancellation would affect, and whether the event can still be cancelled or deleted. Unknown or deleted ids are listed in missing_ids.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.BatchEventsRequest true "Event IDs (max 100)"
+// @Success 200 {object} entity.Response{data=entity.EventsImpactSummary}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/events/impact [post]
+func (rc *ReportController) GetEventsImpact(c *gin.Context) {
+	var req entity.BatchEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	summary, err := rc.ticketService.GetEventsImpact(req.IDs)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "ids are required" ||
+			err.Error() == "too many ids in one request" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to generate impact summary",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Impact summary generated successfully",
+		Data:    summary,
+	})
+}
+
+// GetCategoryTickets godoc
+// @Summary Get tickets for a category (Admin only)
+// @Description Get paginated tickets for all events in a category. Cancelled tickets are excluded unless include_cancelled=true or status=cancelled is given.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param category path string true "Event category"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status"
+// @Param include_cancelled query bool false "Include cancelled tickets when no status is given" default(false)
+// @Param start_date query string false "Purchase date from (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "Purchase date until (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} entity.PaginatedResponse{data=[]entity.Ticket}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/category/{category}/tickets [get]
+func (rc *ReportController) GetCategoryTickets(c *gin.Context) {
+	var pagination entity.Pagination
+	var filter entity.TicketFilter
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid pagination parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter.Category = c.Param("category")
+
+	tickets, meta, err := rc.ticketService.GetAllTickets(&pagination, nil, &filter, nil)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "min total cannot exceed max total" ||
+			err.Error() == "invalid status filter" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve category tickets",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.PaginatedResponse{
+		Success: true,
+		Message: "Category tickets retrieved successfully",
+		Data:    tickets,
+		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
+	})
+}
+
+// GetCategoryReport godoc
+// @Summary Get revenue by category report (Admin only)
+// @Description Get tickets sold and revenue grouped by event category, excluding cancelled tickets
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_date query string false "Purchase date from (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "Purchase date until (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} entity.Response{data=[]entity.CategoryReport}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/by-category [get]
+func (rc *ReportController) GetCategoryReport(c *gin.Context) {
+	var filter entity.DateRangeFilter
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid date range parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	reports, err := rc.ticketService.GetReportByCategory(&filter)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "start date must be before end date" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to generate category report",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Category report generated successfully",
+		Data:    reports,
+	})
+}
+
+// GetLocationReport godoc
+// @Summary Get revenue by location report (Admin only)
+// @Description Get tickets sold and revenue grouped by event location, excluding cancelled tickets
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param start_date query string false "Purchase date from (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "Purchase date until (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} entity.PaginatedResponse{data=[]entity.LocationReport}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /reports/by-location [get]
+func (rc *ReportController) GetLocationReport(c *gin.Context) {
+	var pagination entity.Pagination
+	var filter entity.DateRangeFilter
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid pagination parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid date range parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	reports, meta, err := rc.ticketService.GetReportByLocation(&pagination, &filter)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "start date must be before end date" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to generate location report",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.PaginatedResponse{
+		Success: true,
+		Message: "Location report generated successfully",
+		Data:    reports,
+		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
+	})
+}
+
+// ExportRefunds godoc
+// @Summary Export refunds as CSV (Admin only)
+// @Description Stream refunded (cancelled, paid) tickets as CSV for finance reconciliation. The date range applies to the refund time, which is when the ticket was cancelled. Rows are fetched and flushed in batches of EXPORT_BATCH_SIZE.
+// @Tags Reports
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param start_date query string false "Refunded from (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "Refunded until (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Router /reports/refunds/export [get]
+func (rc *ReportController) ExportRefunds(c *gin.Context) {
+	var filter entity.DateRangeFilter
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid date range parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Reject a reversed range before the export headers are written
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid date range parameters",
+			Error:   "start date must be before end date",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="refunds.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"ticket_id", "user_email", "event_id", "event_name", "amount", "cancellation_fee", "reason", "refunded_at"})
+
+	err := rc.ticketService.ExportRefunds(&filter, func(tickets []entity.Ticket) error {
+		for _, ticket := range tickets {
+			record := []string{
+				ticket.ID,
+				ticket.User.Email,
+				ticket.EventID,
+				ticket.Event.Name,
+				strconv.FormatFloat(ticket.RefundAmount(), 'f', 2, 64),
+				strconv.FormatFloat(ticket.CancellationFee, 'f', 2, 64),
+				ticket.StatusReason,
+				ticket.UpdatedAt.Format(time.RFC3339),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		// Push each batch to the client so large exports stream instead of buffering
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	writer.Flush()
+
+	// Headers are already sent at this point, so failures can only be recorded
+	if err != nil {
+		c.Error(err)
+	}
+}
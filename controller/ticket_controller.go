@@ -1,10 +1,17 @@
 package controller
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"ticketing-system/entity"
 	"ticketing-system/middleware"
 	"ticketing-system/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +35,10 @@ func NewTicketController(ticketService service.TicketService) *TicketController
 // @Success 201 {object} entity.Response{data=entity.Ticket}
 // @Failure 400 {object} entity.Response
 // @Failure 401 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 409 {object} entity.Response
+// @Failure 410 {object} entity.Response
+// @Failure 429 {object} entity.Response
 // @Router /tickets [post]
 func (tc *TicketController) BuyTicket(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
@@ -52,17 +63,29 @@ func (tc *TicketController) BuyTicket(c *gin.Context) {
 	ticket, err := tc.ticketService.BuyTicket(userID, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "user account is not active" ||
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "user account is not active" ||
+			err.Error() == "quantity must be at least 1" ||
+			err.Error() == "invalid delivery email" ||
 			err.Error() == "event is not available for booking" ||
 			err.Error() == "insufficient tickets available" ||
-			err.Error() == "cannot purchase tickets for events starting within an hour" {
+			err.Error() == "sales have closed" ||
+			err.Error() == "sales are temporarily paused" ||
+			err.Error() == "quantity exceeds maximum tickets per purchase" ||
+			err.Error() == "purchase total is too large" ||
+			err.Error() == "cannot purchase tickets this close to event start" {
 			statusCode = http.StatusBadRequest
+		} else if err.Error() == "event has already occurred" {
+			statusCode = http.StatusGone
+		} else if err.Error() == "duplicate purchase, please wait before buying again" {
+			statusCode = http.StatusConflict
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to purchase ticket",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -74,9 +97,143 @@ func (tc *TicketController) BuyTicket(c *gin.Context) {
 	})
 }
 
+// IssueCompTicket godoc
+// @Summary Issue complimentary ticket (Admin only)
+// @Description Issue free tickets to a user, e.g. for staff or press. The ticket is flagged is_comp and records the issuing admin.
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.IssueCompTicketRequest true "Comp ticket data"
+// @Success 201 {object} entity.Response{data=entity.Ticket}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 410 {object} entity.Response
+// @Router /tickets/comp [post]
+func (tc *TicketController) IssueCompTicket(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	var req entity.IssueCompTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ticket, err := tc.ticketService.IssueCompTicket(adminID, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "user account is not active" ||
+			err.Error() == "reason is too long" ||
+			err.Error() == "quantity must be at least 1" ||
+			err.Error() == "quantity exceeds maximum tickets per purchase" ||
+			err.Error() == "event is not available for booking" ||
+			err.Error() == "insufficient tickets available" {
+			statusCode = http.StatusBadRequest
+		} else if err.Error() == "event has already occurred" {
+			statusCode = http.StatusGone
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to issue comp ticket",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entity.Response{
+		Success: true,
+		Message: "Comp ticket issued successfully",
+		Data:    ticket,
+	})
+}
+
+// QuoteTicket godoc
+// @Summary Quote a ticket purchase
+// @Description Price a purchase with the same rules as buying, including tax and fees, without reserving tickets
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.BuyTicketRequest true "Ticket purchase data"
+// @Success 200 {object} entity.Response{data=entity.TicketQuote}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Failure 410 {object} entity.Response
+// @Router /tickets/quote [post]
+func (tc *TicketController) QuoteTicket(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	var req entity.BuyTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	quote, err := tc.ticketService.QuoteTicket(userID, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "user account is not active" ||
+			err.Error() == "quantity must be at least 1" ||
+			err.Error() == "event is not available for booking" ||
+			err.Error() == "insufficient tickets available" ||
+			err.Error() == "sales have closed" ||
+			err.Error() == "sales are temporarily paused" ||
+			err.Error() == "quantity exceeds maximum tickets per purchase" ||
+			err.Error() == "purchase total is too large" ||
+			err.Error() == "cannot purchase tickets this close to event start" {
+			statusCode = http.StatusBadRequest
+		} else if err.Error() == "event has already occurred" {
+			statusCode = http.StatusGone
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to quote ticket",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Ticket quote calculated successfully",
+		Data:    quote,
+	})
+}
+
 // GetAllTickets godoc
 // @Summary Get all tickets (Admin only)
-// @Description Get list of all tickets with pagination, search, and filtering
+// @Description Get list of all tickets with pagination, search, and filtering. Cancelled tickets are excluded unless include_cancelled=true or status=cancelled is given.
 // @Tags Tickets
 // @Accept json
 // @Produce json
@@ -87,8 +244,13 @@ func (tc *TicketController) BuyTicket(c *gin.Context) {
 // @Param user_id query string false "Filter by user ID"
 // @Param event_id query string false "Filter by event ID"
 // @Param status query string false "Filter by status"
-// @Param start_date query string false "Start date filter (RFC3339)"
-// @Param end_date query string false "End date filter (RFC3339)"
+// @Param include_cancelled query bool false "Include cancelled tickets when no status is given" default(false)
+// @Param is_comp query bool false "Only complimentary (true) or paid (false) tickets"
+// @Param min_total query number false "Minimum total price"
+// @Param max_total query number false "Maximum total price"
+// @Param start_date query string false "Start date filter (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (RFC3339 or YYYY-MM-DD)"
+// @Param fields query string false "Comma separated fields to return, e.g. id,status,event"
 // @Success 200 {object} entity.PaginatedResponse{data=[]entity.Ticket}
 // @Failure 400 {object} entity.Response
 // @Failure 401 {object} entity.Response
@@ -98,6 +260,7 @@ func (tc *TicketController) GetAllTickets(c *gin.Context) {
 	var pagination entity.Pagination
 	var search entity.Search
 	var filter entity.TicketFilter
+	var fields entity.TicketFields
 
 	if err := c.ShouldBindQuery(&pagination); err != nil {
 		c.JSON(http.StatusBadRequest, entity.Response{
@@ -126,12 +289,50 @@ func (tc *TicketController) GetAllTickets(c *gin.Context) {
 		return
 	}
 
-	tickets, meta, err := tc.ticketService.GetAllTickets(&pagination, &search, &filter)
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&fields); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid fields parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tickets, meta, err := tc.ticketService.GetAllTickets(&pagination, &search, &filter, &fields)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "min total cannot exceed max total" ||
+			err.Error() == "invalid status filter" ||
+			err.Error() == "invalid fields parameter" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve tickets",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	data, err := projectTickets(tickets, &fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve tickets",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -139,8 +340,271 @@ func (tc *TicketController) GetAllTickets(c *gin.Context) {
 	c.JSON(http.StatusOK, entity.PaginatedResponse{
 		Success: true,
 		Message: "Tickets retrieved successfully",
-		Data:    tickets,
+		Data:    data,
 		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
+	})
+}
+
+// ExportTickets godoc
+// @Summary Export tickets as CSV or JSON (Admin only)
+// @Description Stream all tickets matching the filters as a CSV file or a JSON array. Filter by event_id to export an event's attendee list. Rows are fetched and flushed in batches of EXPORT_BATCH_SIZE.
+// @Tags Tickets
+// @Produce text/csv
+// @Produce json
+// @Security ApiKeyAuth
+// @Param format query string false "Export format (csv or json)" default(csv)
+// @Param q query string false "Search query"
+// @Param user_id query string false "Filter by user ID"
+// @Param event_id query string false "Filter by event ID"
+// @Param status query string false "Filter by status"
+// @Param include_cancelled query bool false "Include cancelled tickets when no status is given" default(false)
+// @Param is_comp query bool false "Only complimentary (true) or paid (false) tickets"
+// @Param min_total query number false "Minimum total price"
+// @Param max_total query number false "Maximum total price"
+// @Param start_date query string false "Start date filter (RFC3339 or YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Router /tickets/export [get]
+func (tc *TicketController) ExportTickets(c *gin.Context) {
+	var search entity.Search
+	var filter entity.TicketFilter
+
+	if err := c.ShouldBindQuery(&search); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid search parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := bindDateQueries(c,
+		dateQuery{name: "start_date", target: &filter.StartDate},
+		dateQuery{name: "end_date", target: &filter.EndDate, endOfDay: true},
+	); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Reject bad filters before the export headers are written
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid export format",
+			Error:   "format must be csv or json",
+		})
+		return
+	}
+
+	filename := "tickets"
+	if eventID := filenameSafe(filter.EventID); eventID != "" {
+		filename = "attendees-" + eventID
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+filename+"."+format+`"`)
+
+	stream := func(fn func(rows []ticketExportRow) error) error {
+		return tc.ticketService.ExportTickets(&search, &filter, func(tickets []entity.Ticket) error {
+			rows := make([]ticketExportRow, len(tickets))
+			for i, ticket := range tickets {
+				rows[i] = newTicketExportRow(ticket)
+			}
+			if err := fn(rows); err != nil {
+				return err
+			}
+
+			// Push each batch to the client so large exports stream instead of buffering
+			c.Writer.Flush()
+			return nil
+		})
+	}
+
+	var err error
+	if format == "json" {
+		err = writeTicketsJSON(c, stream)
+	} else {
+		err = writeTicketsCSV(c, stream)
+	}
+
+	// Headers are already sent at this point, so failures can only be recorded
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+// filenameSafe keeps only letters, digits and dashes of a query value, so it cannot break
+// out of the quoted Content-Disposition filename or inject headers
+func filenameSafe(value string) string {
+	safe := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if len(safe) > 64 {
+		safe = safe[:64]
+	}
+	return safe
+}
+
+// ticketExportRow is the flattened ticket record shared by every export format
+type ticketExportRow struct {
+	TicketID     string    `json:"ticket_id"`
+	EventID      string    `json:"event_id"`
+	EventName    string    `json:"event_name"`
+	UserID       string    `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	UserEmail    string    `json:"user_email"`
+	Quantity     int       `json:"quantity"`
+	TotalPrice   float64   `json:"total_price"`
+	Status       string    `json:"status"`
+	PurchaseDate time.Time `json:"purchase_date"`
+}
+
+func newTicketExportRow(ticket entity.Ticket) ticketExportRow {
+	return ticketExportRow{
+		TicketID:     ticket.ID,
+		EventID:      ticket.EventID,
+		EventName:    ticket.Event.Name,
+		UserID:       ticket.UserID,
+		UserName:     ticket.User.Name,
+		UserEmail:    ticket.User.Email,
+		Quantity:     ticket.Quantity,
+		TotalPrice:   ticket.TotalPrice,
+		Status:       string(ticket.Status),
+		PurchaseDate: ticket.PurchaseDate,
+	}
+}
+
+func writeTicketsCSV(c *gin.Context, stream func(fn func(rows []ticketExportRow) error) error) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{
+		"ticket_id", "event_id", "event_name", "user_id", "user_name", "user_email",
+		"quantity", "total_price", "status", "purchase_date",
+	})
+
+	err := stream(func(rows []ticketExportRow) error {
+		for _, row := range rows {
+			record := []string{
+				row.TicketID,
+				row.EventID,
+				row.EventName,
+				row.UserID,
+				row.UserName,
+				row.UserEmail,
+				strconv.Itoa(row.Quantity),
+				strconv.FormatFloat(row.TotalPrice, 'f', 2, 64),
+				row.Status,
+				row.PurchaseDate.Format(time.RFC3339),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	})
+	writer.Flush()
+
+	return err
+}
+
+func writeTicketsJSON(c *gin.Context, stream func(fn func(rows []ticketExportRow) error) error) error {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	first := true
+	c.Writer.WriteString("[")
+
+	err := stream(func(rows []ticketExportRow) error {
+		for _, row := range rows {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+
+			if !first {
+				c.Writer.WriteString(",")
+			}
+			first = false
+
+			if _, err := c.Writer.Write(data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	c.Writer.WriteString("]")
+
+	return err
+}
+
+// GetMySpending godoc
+// @Summary Get user's spending summary
+// @Description Get the current user's total spend and ticket count (cancelled tickets excluded), with breakdowns by ticket status and by event
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.Response{data=entity.UserSpendingSummary}
+// @Failure 401 {object} entity.Response
+// @Failure 500 {object} entity.Response
+// @Router /profile/spending [get]
+func (tc *TicketController) GetMySpending(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	summary, err := tc.ticketService.GetUserSpending(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve spending summary",
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Spending summary retrieved successfully",
+		Data:    summary,
 	})
 }
 
@@ -153,6 +617,8 @@ func (tc *TicketController) GetAllTickets(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param event_id query string false "Only tickets for this event"
+// @Param fields query string false "Comma separated fields to return, e.g. id,status,event"
 // @Success 200 {object} entity.PaginatedResponse{data=[]entity.Ticket}
 // @Failure 400 {object} entity.Response
 // @Failure 401 {object} entity.Response
@@ -177,12 +643,37 @@ func (tc *TicketController) GetUserTickets(c *gin.Context) {
 		return
 	}
 
-	tickets, meta, err := tc.ticketService.GetUserTickets(userID, &pagination)
+	var fields entity.TicketFields
+	if err := c.ShouldBindQuery(&fields); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid fields parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tickets, meta, err := tc.ticketService.GetUserTickets(userID, c.Query("event_id"), &pagination, &fields)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid fields parameter" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve tickets",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	data, err := projectTickets(tickets, &fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve tickets",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -190,8 +681,71 @@ func (tc *TicketController) GetUserTickets(c *gin.Context) {
 	c.JSON(http.StatusOK, entity.PaginatedResponse{
 		Success: true,
 		Message: "User tickets retrieved successfully",
-		Data:    tickets,
+		Data:    data,
 		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
+	})
+}
+
+// projectTickets returns slim objects when a fields projection was requested, full tickets otherwise
+func projectTickets(tickets []entity.Ticket, fields *entity.TicketFields) (interface{}, error) {
+	list := fields.FieldList()
+	if len(list) == 0 {
+		return tickets, nil
+	}
+	return entity.ProjectTickets(tickets, list)
+}
+
+// GetMyEventTickets godoc
+// @Summary Get user's tickets for an event
+// @Description Get the current user's active tickets for an event, e.g. to warn before a duplicate purchase
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.Response{data=[]entity.Ticket}
+// @Failure 401 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /events/{id}/my-tickets [get]
+func (tc *TicketController) GetMyEventTickets(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Event ID is required",
+		})
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	tickets, err := tc.ticketService.GetUserEventTickets(userID, eventID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to retrieve tickets",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "User event tickets retrieved successfully",
+		Data:    tickets,
 	})
 }
 
@@ -207,6 +761,7 @@ func (tc *TicketController) GetUserTickets(c *gin.Context) {
 // @Failure 401 {object} entity.Response
 // @Failure 403 {object} entity.Response
 // @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
 // @Router /tickets/{id} [get]
 func (tc *TicketController) GetTicketByID(c *gin.Context) {
 	ticketID := c.Param("id")
@@ -220,10 +775,18 @@ func (tc *TicketController) GetTicketByID(c *gin.Context) {
 
 	ticket, err := tc.ticketService.GetTicketByID(ticketID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, entity.Response{
+		// Only a missing record is a 404; anything else is a server failure
+		statusCode := http.StatusNotFound
+		message := "Ticket not found"
+		if err.Error() != "record not found" {
+			statusCode = http.StatusInternalServerError
+			message = "Failed to retrieve ticket"
+		}
+
+		c.JSON(statusCode, entity.Response{
 			Success: false,
-			Message: "Ticket not found",
-			Error:   err.Error(),
+			Message: message,
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -255,7 +818,7 @@ func (tc *TicketController) GetTicketByID(c *gin.Context) {
 
 // UpdateTicketStatus godoc
 // @Summary Update ticket status (Admin only)
-// @Description Update the status of a ticket
+// @Description Update the status of a ticket to used, cancelled or expired. Only active tickets can be marked used or expired.
 // @Tags Tickets
 // @Accept json
 // @Produce json
@@ -291,15 +854,22 @@ func (tc *TicketController) UpdateTicketStatus(c *gin.Context) {
 	ticket, err := tc.ticketService.UpdateTicketStatus(ticketID, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "cannot update cancelled ticket" ||
-			err.Error() == "can only mark active tickets as used" {
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot update cancelled ticket" ||
+			err.Error() == "reason is too long" ||
+			err.Error() == "status must be used, cancelled or expired" ||
+			err.Error() == "can only mark active tickets as used" ||
+			err.Error() == "can only expire active tickets" ||
+			err.Error() == "check-in window not yet open" ||
+			err.Error() == "check-in window closed" {
 			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to update ticket status",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -313,12 +883,13 @@ func (tc *TicketController) UpdateTicketStatus(c *gin.Context) {
 
 // CancelTicket godoc
 // @Summary Cancel ticket
-// @Description Cancel a user's ticket
+// @Description Cancel a user's ticket. Cancelling an already cancelled ticket of your own succeeds and returns it unchanged.
 // @Tags Tickets
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path string true "Ticket ID"
+// @Param request body entity.CancelTicketRequest false "Optional cancellation reason"
 // @Success 200 {object} entity.Response{data=entity.Ticket}
 // @Failure 400 {object} entity.Response
 // @Failure 401 {object} entity.Response
@@ -344,24 +915,99 @@ func (tc *TicketController) CancelTicket(c *gin.Context) {
 		return
 	}
 
-	ticket, err := tc.ticketService.CancelTicket(ticketID, userID)
+	// The request body is optional; it only carries a cancellation reason
+	var req entity.CancelTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ticket, err := tc.ticketService.CancelTicket(ticketID, userID, &req)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "you can only cancel your own tickets" {
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "you can only cancel your own tickets" {
 			statusCode = http.StatusForbidden
 		} else if err.Error() == "ticket cannot be cancelled" ||
-			err.Error() == "cannot cancel tickets within 2 hours of event start" {
+			err.Error() == "reason is too long" ||
+			err.Error() == "cannot cancel tickets this close to event start" {
 			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to cancel ticket",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Ticket cancelled successfully",
+		Data:    ticket,
+	})
+}
+
+// ForceCancelTicket godoc
+// @Summary Force-cancel ticket (Admin only)
+// @Description Cancel any active ticket, even past the cancellation cutoff, returning its seats to the event. A reason is required; the ticket records the admin in cancelled_by and the cancellation is logged.
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Param request body entity.ForceCancelTicketRequest true "Cancellation reason"
+// @Success 200 {object} entity.Response{data=entity.Ticket}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 403 {object} entity.Response
+// @Failure 404 {object} entity.Response
+// @Router /tickets/{id}/force-cancel [patch]
+func (tc *TicketController) ForceCancelTicket(c *gin.Context) {
+	adminID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	var req entity.ForceCancelTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	ticket, err := tc.ticketService.ForceCancelTicket(c.Param("id"), adminID, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "reason is required" ||
+			err.Error() == "reason is too long" ||
+			err.Error() == "ticket cannot be cancelled" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Failed to cancel ticket",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, entity.Response{
 		Success: true,
 		Message: "Ticket cancelled successfully",
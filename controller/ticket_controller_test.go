@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"ticketing-system/entity"
+	"ticketing-system/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportTicketService streams its tickets in batches of batchSize, like the repository does
+type exportTicketService struct {
+	service.TicketService
+	tickets   []entity.Ticket
+	batchSize int
+	batches   int
+}
+
+func (s *exportTicketService) ExportTickets(search *entity.Search, filter *entity.TicketFilter, fn func(tickets []entity.Ticket) error) error {
+	for start := 0; start < len(s.tickets); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(s.tickets) {
+			end = len(s.tickets)
+		}
+		s.batches++
+		if err := fn(s.tickets[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newExportRouter(svc service.TicketService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tickets/export", NewTicketController(svc).ExportTickets)
+	return router
+}
+
+func exportTestTickets(n int) []entity.Ticket {
+	tickets := make([]entity.Ticket, n)
+	for i := range tickets {
+		tickets[i] = entity.Ticket{
+			ID:         fmt.Sprintf("ticket-%d", i),
+			EventID:    "event-1",
+			UserID:     "user-1",
+			Quantity:   1,
+			TotalPrice: 10,
+			Status:     entity.TicketStatusActive,
+		}
+	}
+	return tickets
+}
+
+func TestExportTicketsStreamsEveryBatch(t *testing.T) {
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			svc := &exportTicketService{tickets: exportTestTickets(7), batchSize: 3}
+			recorder := httptest.NewRecorder()
+			newExportRouter(svc).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tickets/export?format="+format, nil))
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body.String())
+			}
+			if svc.batches != 3 {
+				t.Fatalf("batches = %d, want 3", svc.batches)
+			}
+
+			var ids []string
+			if format == "csv" {
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				if err != nil {
+					t.Fatalf("parse csv: %v", err)
+				}
+				if len(records) == 0 || records[0][0] != "ticket_id" {
+					t.Fatalf("missing csv header: %v", records)
+				}
+				for _, record := range records[1:] {
+					ids = append(ids, record[0])
+				}
+			} else {
+				var rows []ticketExportRow
+				if err := json.Unmarshal(recorder.Body.Bytes(), &rows); err != nil {
+					t.Fatalf("parse json: %v\n%s", err, recorder.Body.String())
+				}
+				for _, row := range rows {
+					ids = append(ids, row.TicketID)
+				}
+			}
+
+			if len(ids) != 7 {
+				t.Fatalf("exported %d rows, want 7", len(ids))
+			}
+			for i, id := range ids {
+				if want := fmt.Sprintf("ticket-%d", i); id != want {
+					t.Errorf("row %d = %s, want %s", i, id, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportTicketsFilename(t *testing.T) {
+	tests := []struct {
+		eventID string
+		want    string
+	}{
+		{"", `attachment; filename="tickets.csv"`},
+		{"3f2b6c1e-0d4a-4b8e-9c1f-2a7d5e6f8b90", `attachment; filename="attendees-3f2b6c1e-0d4a-4b8e-9c1f-2a7d5e6f8b90.csv"`},
+		{"evil\"; filename=x.exe", `attachment; filename="attendees-evilfilenamexexe.csv"`},
+		{"a\r\nSet-Cookie: x=1", `attachment; filename="attendees-aSet-Cookiex1.csv"`},
+		{"../../etc/passwd", `attachment; filename="attendees-etcpasswd.csv"`},
+		{"\"';", `attachment; filename="tickets.csv"`},
+	}
+
+	for _, tt := range tests {
+		svc := &exportTicketService{batchSize: 1}
+		target := "/tickets/export?event_id=" + url.QueryEscape(tt.eventID)
+		recorder := httptest.NewRecorder()
+		newExportRouter(svc).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if got := recorder.Header().Get("Content-Disposition"); got != tt.want {
+			t.Errorf("event_id %q: Content-Disposition = %s, want %s", tt.eventID, got, tt.want)
+		}
+	}
+}
+
+func TestFilenameSafe(t *testing.T) {
+	long := strings.Repeat("a", 100)
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"abc-DEF-123", "abc-DEF-123"},
+		{"a b/c\\d.e", "abcde"},
+		{"événement", "vnement"},
+		{long, long[:64]},
+	}
+
+	for _, tt := range tests {
+		if got := filenameSafe(tt.value); got != tt.want {
+			t.Errorf("filenameSafe(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -49,7 +49,7 @@ func (uc *UserController) Register(c *gin.Context) {
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Registration failed",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -93,7 +93,7 @@ func (uc *UserController) Login(c *gin.Context) {
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Login failed",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -105,6 +105,50 @@ func (uc *UserController) Login(c *gin.Context) {
 	})
 }
 
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and refresh token. The presented refresh token is revoked; presenting it again revokes all of the user's refresh tokens.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body entity.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} entity.Response{data=entity.LoginResponse}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Router /refresh [post]
+func (uc *UserController) RefreshToken(c *gin.Context) {
+	var req entity.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := uc.userService.RefreshToken(req.RefreshToken)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid or expired refresh token" || err.Error() == "account is deactivated" {
+			statusCode = http.StatusUnauthorized
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Token refresh failed",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Token refreshed successfully",
+		Data:    response,
+	})
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Description Get current user profile
@@ -115,6 +159,7 @@ func (uc *UserController) Login(c *gin.Context) {
 // @Success 200 {object} entity.Response{data=entity.User}
 // @Failure 401 {object} entity.Response
 // @Failure 404 {object} entity.Response
+// @Failure 500 {object} entity.Response
 // @Router /profile [get]
 func (uc *UserController) GetProfile(c *gin.Context) {
 	userID, exists := middleware.GetCurrentUserID(c)
@@ -128,10 +173,18 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 
 	user, err := uc.userService.GetProfile(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, entity.Response{
+		// Only a missing record is a 404; anything else is a server failure
+		statusCode := http.StatusNotFound
+		message := "User not found"
+		if err.Error() != "record not found" {
+			statusCode = http.StatusInternalServerError
+			message = "Failed to retrieve profile"
+		}
+
+		c.JSON(statusCode, entity.Response{
 			Success: false,
-			Message: "User not found",
-			Error:   err.Error(),
+			Message: message,
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -145,12 +198,12 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update current user profile
+// @Description Update current user profile. A changed email is not applied immediately; a confirmation link is sent to the new address instead.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param request body entity.User true "User update data"
+// @Param request body entity.UpdateProfileRequest true "Profile update data"
 // @Success 200 {object} entity.Response{data=entity.User}
 // @Failure 400 {object} entity.Response
 // @Failure 401 {object} entity.Response
@@ -166,7 +219,7 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var updateData entity.User
+	var updateData entity.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		c.JSON(http.StatusBadRequest, entity.Response{
 			Success: false,
@@ -181,12 +234,14 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "email already taken" {
 			statusCode = http.StatusConflict
+		} else if err.Error() == "invalid email address" {
+			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Profile update failed",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
@@ -198,9 +253,113 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 	})
 }
 
+// RequestEmailChange godoc
+// @Summary Request email change
+// @Description Email a confirmation link to the new address. The account keeps its current email until the link is opened.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body entity.EmailChangeRequest true "New email address"
+// @Success 202 {object} entity.Response{data=entity.User}
+// @Failure 400 {object} entity.Response
+// @Failure 401 {object} entity.Response
+// @Failure 409 {object} entity.Response
+// @Router /profile/email [post]
+func (uc *UserController) RequestEmailChange(c *gin.Context) {
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, entity.Response{
+			Success: false,
+			Message: "Authentication required",
+		})
+		return
+	}
+
+	var req entity.EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := uc.userService.RequestEmailChange(userID, req.Email)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "email already taken" {
+			statusCode = http.StatusConflict
+		} else if err.Error() == "invalid email address" ||
+			err.Error() == "email is unchanged" {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Email change request failed",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, entity.Response{
+		Success: true,
+		Message: "Confirmation email sent to the new address",
+		Data:    user,
+	})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Apply a pending email change using the token from the confirmation link. This is a POST so link prefetchers cannot trigger it.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body entity.ConfirmEmailChangeRequest true "Confirmation token"
+// @Success 200 {object} entity.Response{data=entity.User}
+// @Failure 400 {object} entity.Response
+// @Failure 409 {object} entity.Response
+// @Router /profile/email/confirm [post]
+func (uc *UserController) ConfirmEmailChange(c *gin.Context) {
+	var req entity.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid request format",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := uc.userService.ConfirmEmailChange(req.Token)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid or expired token" {
+			statusCode = http.StatusBadRequest
+		} else if err.Error() == "email already taken" {
+			statusCode = http.StatusConflict
+		}
+
+		c.JSON(statusCode, entity.Response{
+			Success: false,
+			Message: "Email change confirmation failed",
+			Error:   errorDetail(c, statusCode, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.Response{
+		Success: true,
+		Message: "Email changed successfully",
+		Data:    user,
+	})
+}
+
 // GetAllUsers godoc
 // @Summary Get all users (Admin only)
-// @Description Get list of all users with pagination and search
+// @Description Get list of all users with pagination, search and a purchase history filter
 // @Tags User
 // @Accept json
 // @Produce json
@@ -208,6 +367,7 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param q query string false "Search query"
+// @Param has_tickets query bool false "Only users who have (true) or have never (false) purchased a ticket"
 // @Success 200 {object} entity.PaginatedResponse{data=[]entity.User}
 // @Failure 401 {object} entity.Response
 // @Failure 403 {object} entity.Response
@@ -234,12 +394,22 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 		return
 	}
 
-	users, meta, err := uc.userService.GetAllUsers(&pagination, &search)
+	var filter entity.UserFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, entity.Response{
+			Success: false,
+			Message: "Invalid filter parameters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	users, meta, err := uc.userService.GetAllUsers(&pagination, &search, &filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, entity.Response{
 			Success: false,
 			Message: "Failed to retrieve users",
-			Error:   err.Error(),
+			Error:   errorDetail(c, http.StatusInternalServerError, err),
 		})
 		return
 	}
@@ -249,6 +419,7 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 		Message: "Users retrieved successfully",
 		Data:    users,
 		Meta:    *meta,
+		Links:   paginationLinks(c, meta),
 	})
 }
 
@@ -279,14 +450,16 @@ func (uc *UserController) DeleteUser(c *gin.Context) {
 	err := uc.userService.DeleteUser(userID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		if err.Error() == "cannot delete admin user" {
+		if err.Error() == "record not found" {
+			statusCode = http.StatusNotFound
+		} else if err.Error() == "cannot delete admin user" {
 			statusCode = http.StatusBadRequest
 		}
 
 		c.JSON(statusCode, entity.Response{
 			Success: false,
 			Message: "Failed to delete user",
-			Error:   err.Error(),
+			Error:   errorDetail(c, statusCode, err),
 		})
 		return
 	}
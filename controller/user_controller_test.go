@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"ticketing-system/entity"
+	"ticketing-system/repository"
+	"ticketing-system/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// memUserRepository keeps users in memory for the profile handlers
+type memUserRepository struct {
+	repository.UserRepository
+	users map[string]entity.User
+}
+
+func (r *memUserRepository) GetByID(id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (r *memUserRepository) GetByEmail(email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memUserRepository) Update(user *entity.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+func newProfileRouter(t *testing.T, repo *memUserRepository, userID string) *gin.Engine {
+	t.Helper()
+
+	emails, err := service.LoadEmailTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := service.NewPasswordHasher(service.HashAlgorithmBcrypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userService := service.NewUserService(repo, hasher, service.NewLogNotifier(), emails,
+		service.EmailChangeSettings{ConfirmURL: "https://example.com/confirm-email?token=", TokenTTL: time.Hour},
+		"secret", time.Hour, time.Hour, time.Hour)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+	router.PUT("/profile", NewUserController(userService).UpdateProfile)
+	return router
+}
+
+// TestUpdateProfileIgnoresPrivilegedFields submits role and is_active alongside the name;
+// only the name may change
+func TestUpdateProfileIgnoresPrivilegedFields(t *testing.T) {
+	repo := &memUserRepository{users: map[string]entity.User{
+		"u1": {ID: "u1", Email: "user@example.com", Name: "Old Name", Role: entity.RoleUser, IsActive: true},
+	}}
+
+	body := `{"name":"New Name","role":"admin","is_active":false,"id":"other","password":"x"}`
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPut, "/profile", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	newProfileRouter(t, repo, "u1").ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body.String())
+	}
+
+	saved := repo.users["u1"]
+	if saved.Name != "New Name" {
+		t.Errorf("name = %q, want New Name", saved.Name)
+	}
+	if saved.Role != entity.RoleUser || !saved.IsActive || saved.Password != "" || len(repo.users) != 1 {
+		t.Errorf("privileged fields changed: %+v", saved)
+	}
+}
+
+func TestUpdateProfileRejectsDisplayNameEmail(t *testing.T) {
+	repo := &memUserRepository{users: map[string]entity.User{
+		"u1": {ID: "u1", Email: "user@example.com", Name: "User", Role: entity.RoleUser, IsActive: true},
+	}}
+
+	body := `{"email":"Mallory <mallory@example.com>"}`
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPut, "/profile", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	newProfileRouter(t, repo, "u1").ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400; body %s", recorder.Code, recorder.Body.String())
+	}
+	if saved := repo.users["u1"]; saved.PendingEmail != "" {
+		t.Errorf("pending email = %q, want none", saved.PendingEmail)
+	}
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,27 +12,75 @@ import (
 type EventStatus string
 
 const (
+	EventStatusDraft     EventStatus = "draft"
 	EventStatusActive    EventStatus = "active"
 	EventStatusOngoing   EventStatus = "ongoing"
 	EventStatusCompleted EventStatus = "completed"
 	EventStatusCancelled EventStatus = "cancelled"
 )
 
+type EventVisibility string
+
+const (
+	EventVisibilityPublic  EventVisibility = "public"
+	EventVisibilityPrivate EventVisibility = "private"
+)
+
+// eventStatusTransitions lists the statuses each status may move to
+var eventStatusTransitions = map[EventStatus][]EventStatus{
+	EventStatusDraft:   {EventStatusActive, EventStatusCancelled},
+	EventStatusActive:  {EventStatusOngoing, EventStatusCancelled},
+	EventStatusOngoing: {EventStatusCompleted, EventStatusCancelled},
+}
+
+// IsValid reports whether s is one of the known event statuses
+func (s EventStatus) IsValid() bool {
+	switch s {
+	case EventStatusDraft, EventStatusActive, EventStatusOngoing, EventStatusCompleted, EventStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an event may move from s to next
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	for _, allowed := range eventStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Event struct {
-	ID          string         `json:"id" gorm:"type:varchar(36);primary_key"`
-	Name        string         `json:"name" gorm:"uniqueIndex;not null" validate:"required,min=3"`
-	Description string         `json:"description" gorm:"type:text"`
-	Category    string         `json:"category" gorm:"not null" validate:"required"`
-	Capacity    int            `json:"capacity" gorm:"not null" validate:"required,min=1"`
-	Available   int            `json:"available" gorm:"not null"`
-	Price       float64        `json:"price" gorm:"not null" validate:"required,min=0"`
-	Location    string         `json:"location" gorm:"not null" validate:"required"`
-	EventDate   time.Time      `json:"event_date" gorm:"not null" validate:"required"`
-	Status      EventStatus    `json:"status" gorm:"type:enum('active','ongoing','completed','cancelled');default:'active'"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
-	
+	ID             string          `json:"id" gorm:"type:varchar(36);primary_key"`
+	Name           string          `json:"name" gorm:"type:varchar(191);index;not null" validate:"required,min=3"` // Unique among non-deleted events, ignoring case
+	Description    string          `json:"description" gorm:"type:text"`
+	Category       string          `json:"category" gorm:"not null" validate:"required"`
+	Capacity       int             `json:"capacity" gorm:"not null" validate:"required,min=1"`
+	Available      int             `json:"available" gorm:"not null"`
+	Price          float64         `json:"price" gorm:"not null" validate:"required,min=0"`
+	TaxRate        float64         `json:"tax_rate" gorm:"not null;default:0" validate:"min=0"` // Percentage added on top of the price
+	Location       string          `json:"location" gorm:"not null" validate:"required"`
+	EventDate      time.Time       `json:"event_date" gorm:"not null" validate:"required"`
+	SaleEndsAt     *time.Time      `json:"sale_ends_at,omitempty"`
+	SalesPaused    bool            `json:"sales_paused" gorm:"not null;default:false"` // Blocks purchases without changing status
+	MinAge         int             `json:"min_age" gorm:"not null;default:0" validate:"min=0"`
+	AgeRestriction string          `json:"age_restriction,omitempty" gorm:"type:varchar(255)"`
+	Status         EventStatus     `json:"status" gorm:"type:varchar(20);default:'active';check:chk_events_status,status IN ('draft','active','ongoing','completed','cancelled')"`
+	Visibility     EventVisibility `json:"visibility" gorm:"type:varchar(20);default:'public';check:chk_events_visibility,visibility IN ('public','private')"`
+	ShareToken     string          `json:"share_token,omitempty" gorm:"type:varchar(64);index"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `json:"deleted_at" gorm:"index"`
+
+	// Minutes after the start that check-in stays open, nil uses the global setting
+	CheckInGrace *int `json:"check_in_grace_minutes,omitempty" gorm:"column:check_in_grace_minutes"`
+
+	// Admin who created the event, empty for events created before it was recorded.
+	// Kept out of responses since events are public; used by the created_by_email filter.
+	CreatedBy string `json:"-" gorm:"type:varchar(36);index"`
+
 	// Relationships
 	Tickets []Ticket `json:"tickets,omitempty" gorm:"foreignKey:EventID"`
 }
@@ -45,40 +95,245 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// EventStatusChange records one status transition of an event. ChangedBy holds the
+// acting admin and is empty for automatic transitions.
+type EventStatusChange struct {
+	ID         string      `json:"id" gorm:"type:varchar(36);primary_key"`
+	EventID    string      `json:"event_id" gorm:"type:varchar(36);not null;index"`
+	FromStatus EventStatus `json:"from_status,omitempty" gorm:"type:varchar(20)"` // Empty for the initial status
+	ToStatus   EventStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	ChangedBy  string      `json:"changed_by,omitempty" gorm:"type:varchar(36)"`
+	ChangedAt  time.Time   `json:"changed_at" gorm:"not null"`
+}
+
+func (EventStatusChange) TableName() string {
+	return "event_status_history"
+}
+
+func (c *EventStatusChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.ChangedAt.IsZero() {
+		c.ChangedAt = time.Now()
+	}
+	return nil
+}
+
 func (e *Event) IsAvailable() bool {
 	return e.Available > 0 && e.Status == EventStatusActive
 }
 
+// IsSaleClosed reports whether the optional sale-close time has passed
+func (e *Event) IsSaleClosed(now time.Time) bool {
+	return e.SaleEndsAt != nil && now.After(*e.SaleEndsAt)
+}
+
 func (e *Event) CanBeModified() bool {
-	return e.Status == EventStatusActive
+	return e.Status == EventStatusActive || e.Status == EventStatusDraft
+}
+
+func (e *Event) IsPrivate() bool {
+	return e.Visibility == EventVisibilityPrivate
+}
+
+func (e *Event) IsDraft() bool {
+	return e.Status == EventStatusDraft
 }
 
 type CreateEventRequest struct {
-	Name        string    `json:"name" validate:"required,min=3"`
-	Description string    `json:"description"`
-	Category    string    `json:"category" validate:"required"`
-	Capacity    int       `json:"capacity" validate:"required,min=1"`
-	Price       float64   `json:"price" validate:"required,min=0"`
-	Location    string    `json:"location" validate:"required"`
-	EventDate   time.Time `json:"event_date" validate:"required"`
+	Name           string          `json:"name" validate:"required,min=3"`
+	Description    string          `json:"description"`
+	Category       string          `json:"category" validate:"required"`
+	Capacity       int             `json:"capacity" validate:"required,min=1"`
+	Price          float64         `json:"price" validate:"required,min=0"`
+	TaxRate        float64         `json:"tax_rate,omitempty" validate:"omitempty,min=0"`
+	Location       string          `json:"location" validate:"required"`
+	EventDate      time.Time       `json:"event_date" validate:"required"`
+	SaleEndsAt     *time.Time      `json:"sale_ends_at,omitempty"`
+	CheckInGrace   *int            `json:"check_in_grace_minutes,omitempty" validate:"omitempty,min=0"`
+	Draft          *bool           `json:"draft,omitempty"`
+	MinAge         int             `json:"min_age,omitempty" validate:"omitempty,min=0"`
+	AgeRestriction string          `json:"age_restriction,omitempty" validate:"omitempty,max=255"`
+	Visibility     EventVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=public private"`
 }
 
 type UpdateEventRequest struct {
-	Name        *string    `json:"name,omitempty" validate:"omitempty,min=3"`
-	Description *string    `json:"description,omitempty"`
-	Category    *string    `json:"category,omitempty"`
-	Capacity    *int       `json:"capacity,omitempty" validate:"omitempty,min=1"`
-	Price       *float64   `json:"price,omitempty" validate:"omitempty,min=0"`
-	Location    *string    `json:"location,omitempty"`
-	EventDate   *time.Time `json:"event_date,omitempty"`
+	Name           *string          `json:"name,omitempty" validate:"omitempty,min=3"`
+	Description    *string          `json:"description,omitempty"`
+	Category       *string          `json:"category,omitempty"`
+	Capacity       *int             `json:"capacity,omitempty" validate:"omitempty,min=1"`
+	Price          *float64         `json:"price,omitempty" validate:"omitempty,min=0"`
+	TaxRate        *float64         `json:"tax_rate,omitempty" validate:"omitempty,min=0"`
+	Location       *string          `json:"location,omitempty"`
+	EventDate      *time.Time       `json:"event_date,omitempty"`
+	SaleEndsAt     *time.Time       `json:"sale_ends_at,omitempty"`
+	CheckInGrace   *int             `json:"check_in_grace_minutes,omitempty" validate:"omitempty,min=0"`
+	Visibility     *EventVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=public private"`
+	MinAge         *int             `json:"min_age,omitempty" validate:"omitempty,min=0"`
+	AgeRestriction *string          `json:"age_restriction,omitempty" validate:"omitempty,max=255"`
+}
+
+// TrendingEvent is an event ranked by tickets sold within the trending window
+type TrendingEvent struct {
+	Event
+	RecentTicketsSold int `json:"recent_tickets_sold"`
+}
+
+type EventSalesRequest struct {
+	Paused *bool `json:"paused" validate:"required"`
+}
+
+type BatchEventsRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+type BulkEventStatusRequest struct {
+	IDs    []string    `json:"ids" validate:"required,min=1"`
+	Status EventStatus `json:"status" validate:"required,oneof=ongoing completed"`
+}
+
+type BulkEventStatusResult struct {
+	ID             string      `json:"id"`
+	Success        bool        `json:"success"`
+	PreviousStatus EventStatus `json:"previous_status,omitempty"`
+	Status         EventStatus `json:"status,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+type EventCancellationSummary struct {
+	EventID          string    `json:"event_id"`
+	EventName        string    `json:"event_name"`
+	TicketsCancelled int       `json:"tickets_cancelled"`
+	QuantityReleased int       `json:"quantity_released"`
+	RefundTotal      float64   `json:"refund_total"`
+	AlreadyCancelled bool      `json:"already_cancelled"`
+	CancelledAt      time.Time `json:"cancelled_at"`
 }
 
 type EventFilter struct {
-	Category  string `form:"category"`
-	Status    string `form:"status"`
-	Location  string `form:"location"`
-	MinPrice  *float64 `form:"min_price"`
-	MaxPrice  *float64 `form:"max_price"`
-	StartDate *time.Time `form:"start_date"`
-	EndDate   *time.Time `form:"end_date"`
-} 
\ No newline at end of file
+	Category   string     `form:"category"`
+	Categories string     `form:"categories"`
+	Status     string     `form:"status"`
+	Statuses   string     `form:"statuses"`
+	Location   string     `form:"location"`
+	MinPrice   *float64   `form:"min_price"`
+	MaxPrice   *float64   `form:"max_price"`
+	StartDate  *time.Time `form:"-"` // Parsed by the controller, see bindDateQueries
+	EndDate    *time.Time `form:"-"`
+
+	// MinAvailable keeps events with at least this many seats left, e.g. for group bookings
+	MinAvailable *int `form:"min_available"`
+
+	// UpdatedSince and IncludeDeleted support incremental client syncs
+	UpdatedSince   *time.Time `form:"-"`
+	IncludeDeleted bool       `form:"include_deleted"`
+
+	// Fields limits the response to these comma separated fields, e.g. "id,name"
+	Fields string `form:"fields"`
+
+	// CreatedByEmail keeps events created by this admin, admin only
+	CreatedByEmail string `form:"created_by_email"`
+
+	// IncludeDrafts and IncludePrivate are set by the controller for admins, never bound from the query
+	IncludeDrafts  bool `form:"-"`
+	IncludePrivate bool `form:"-"`
+}
+
+// CategoryList merges the single category and comma separated categories filters
+func (f *EventFilter) CategoryList() []string {
+	return splitCommaList(f.Category + "," + f.Categories)
+}
+
+// StatusList merges the single status and comma separated statuses filters
+func (f *EventFilter) StatusList() []string {
+	return splitCommaList(f.Status + "," + f.Statuses)
+}
+
+// eventFieldColumns is the allowlist of fields that may be requested through EventFilter.Fields
+var eventFieldColumns = map[string]string{
+	"id":              "id",
+	"name":            "name",
+	"description":     "description",
+	"category":        "category",
+	"capacity":        "capacity",
+	"available":       "available",
+	"price":           "price",
+	"tax_rate":        "tax_rate",
+	"location":        "location",
+	"event_date":      "event_date",
+	"sale_ends_at":    "sale_ends_at",
+	"sales_paused":    "sales_paused",
+	"min_age":         "min_age",
+	"age_restriction": "age_restriction",
+	"status":          "status",
+	"visibility":      "visibility",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
+	"deleted_at":      "deleted_at",
+
+	"check_in_grace_minutes": "check_in_grace_minutes",
+}
+
+// FieldList returns the requested response fields, empty when the full event is wanted
+func (f *EventFilter) FieldList() []string {
+	return splitCommaList(f.Fields)
+}
+
+// FieldColumns maps the requested fields to columns, reporting false for unknown fields
+func (f *EventFilter) FieldColumns() ([]string, bool) {
+	var columns []string
+	for _, field := range f.FieldList() {
+		column, ok := eventFieldColumns[field]
+		if !ok {
+			return nil, false
+		}
+		columns = append(columns, column)
+	}
+	return columns, true
+}
+
+// ProjectEvents reduces each event to the given JSON fields for slim list payloads
+func ProjectEvents(events []Event, fields []string) ([]map[string]json.RawMessage, error) {
+	return projectFields(events, fields)
+}
+
+// projectFields reduces each item to the given top-level JSON fields
+func projectFields[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+
+		slim := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				slim[field] = value
+			}
+		}
+		projected = append(projected, slim)
+	}
+	return projected, nil
+}
+
+// splitCommaList splits a comma separated query value, dropping blanks and duplicates
+func splitCommaList(value string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventFilterStatusList(t *testing.T) {
+	tests := []struct {
+		status   string
+		statuses string
+		want     []string
+	}{
+		{"", "", nil},
+		{"active", "", []string{"active"}},
+		{"", "draft,active", []string{"draft", "active"}},
+		{"active", " ongoing , completed ", []string{"active", "ongoing", "completed"}},
+		{"active", "active,ongoing,active", []string{"active", "ongoing"}},
+		{"", ",,", nil},
+		{"", "Active", []string{"Active"}}, // values are kept as given and validated by the service
+	}
+
+	for _, tt := range tests {
+		filter := EventFilter{Status: tt.status, Statuses: tt.statuses}
+		if got := filter.StatusList(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("StatusList(status=%q, statuses=%q) = %v, want %v", tt.status, tt.statuses, got, tt.want)
+		}
+	}
+}
@@ -16,13 +16,26 @@ type PaginationMeta struct {
 	Limit       int   `json:"limit"`
 }
 
+type PaginationLinks struct {
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
+}
+
 type PaginatedResponse struct {
-	Success bool           `json:"success"`
-	Message string         `json:"message"`
-	Data    interface{}    `json:"data"`
-	Meta    PaginationMeta `json:"meta"`
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    interface{}      `json:"data"`
+	Meta    PaginationMeta   `json:"meta"`
+	Links   *PaginationLinks `json:"links,omitempty"`
 }
 
+const (
+	DefaultPageLimit = 10
+	MaxPageLimit     = 100
+)
+
 type Pagination struct {
 	Page  int `form:"page" json:"page"`
 	Limit int `form:"limit" json:"limit"`
@@ -37,10 +50,10 @@ func (p *Pagination) GetOffset() int {
 
 func (p *Pagination) GetLimit() int {
 	if p.Limit <= 0 {
-		p.Limit = 10
+		p.Limit = DefaultPageLimit
 	}
-	if p.Limit > 100 {
-		p.Limit = 100
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
 	}
 	return p.Limit
 }
@@ -51,25 +64,157 @@ type Search struct {
 
 // Report structures
 type ReportSummary struct {
-	TotalTicketsSold int     `json:"total_tickets_sold"`
-	TotalRevenue     float64 `json:"total_revenue"`
-	TotalEvents      int     `json:"total_events"`
-	ActiveEvents     int     `json:"active_events"`
-	TotalUsers       int     `json:"total_users"`
+	TotalTicketsSold int       `json:"total_tickets_sold"`
+	PaidTickets      int       `json:"paid_tickets"`
+	CompTickets      int       `json:"comp_tickets"`
+	TotalRevenue     float64   `json:"total_revenue"`
+	RevenueBeforeTax float64   `json:"revenue_before_tax"`
+	TaxCollected     float64   `json:"tax_collected"`
+	FaceValue        float64   `json:"face_value"`
+	FeesCollected    float64   `json:"fees_collected"`
+	TotalEvents      int       `json:"total_events"`
+	ActiveEvents     int       `json:"active_events"`
+	TotalUsers       int       `json:"total_users"`
 	GeneratedAt      time.Time `json:"generated_at"`
+
+	// Kept from cancelled tickets and included in total_revenue
+	CancellationFees float64 `json:"cancellation_fees"`
 }
 
 type EventReport struct {
-	EventID       string  `json:"event_id"`
-	EventName     string  `json:"event_name"`
-	TicketsSold   int     `json:"tickets_sold"`
-	Revenue       float64 `json:"revenue"`
-	Capacity      int     `json:"capacity"`
-	Available     int     `json:"available"`
-	SalesRate     float64 `json:"sales_rate"` // Percentage of tickets sold
+	EventID          string  `json:"event_id"`
+	EventName        string  `json:"event_name"`
+	TicketsSold      int     `json:"tickets_sold"`
+	PaidTickets      int     `json:"paid_tickets"`
+	CompTickets      int     `json:"comp_tickets"`
+	Revenue          float64 `json:"revenue"`
+	RevenueBeforeTax float64 `json:"revenue_before_tax"`
+	TaxCollected     float64 `json:"tax_collected"`
+	FaceValue        float64 `json:"face_value"`
+	FeesCollected    float64 `json:"fees_collected"`
+	Capacity         int     `json:"capacity"`
+	Available        int     `json:"available"`
+	SalesRate        float64 `json:"sales_rate"` // Percentage of tickets sold
+	CheckedIn        int     `json:"checked_in"`
+	CheckInRate      float64 `json:"check_in_rate"` // Percentage of sold tickets checked in
+
+	CancelledTickets    int                       `json:"cancelled_tickets"`
+	CancellationReasons []CancellationReasonCount `json:"cancellation_reasons"`
+
+	// Kept from cancelled tickets and included in revenue
+	CancellationFees float64 `json:"cancellation_fees"`
+}
+
+// DetailedEventReport extends EventReport with refund and ticket status figures
+// for the organizer dashboard
+type DetailedEventReport struct {
+	EventReport
+
+	GrossRevenue    float64             `json:"gross_revenue"` // Paid tickets including later-cancelled ones
+	RefundTotal     float64             `json:"refund_total"`
+	NetRevenue      float64             `json:"net_revenue"`
+	StatusBreakdown []TicketStatusCount `json:"status_breakdown"`
+}
+
+// EventSalesTimeline buckets an event's paid sales from its creation until now
+type EventSalesTimeline struct {
+	EventID  string               `json:"event_id"`
+	Interval string               `json:"interval"` // hour, day or week
+	Points   []SalesTimelinePoint `json:"points"`
+}
+
+type SalesTimelinePoint struct {
+	Period      time.Time `json:"period"` // Start of the bucket
+	TicketsSold int       `json:"tickets_sold"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// UserSpendingSummary is a user's own purchase activity. Cancelled tickets were
+// refunded, so they appear in the status breakdown but not in the totals.
+type UserSpendingSummary struct {
+	TotalSpent  float64          `json:"total_spent"`  // Includes tax and fees
+	TicketCount int              `json:"ticket_count"` // Tickets not cancelled
+	ByStatus    []StatusSpending `json:"by_status"`
+	ByEvent     []EventSpending  `json:"by_event"`
+}
+
+type StatusSpending struct {
+	Status TicketStatus `json:"status"`
+	Count  int          `json:"count"`
+	Amount float64      `json:"amount"`
+}
+
+type EventSpending struct {
+	EventID   string  `json:"event_id"`
+	EventName string  `json:"event_name"`
+	Count     int     `json:"count"`
+	Amount    float64 `json:"amount"`
+}
+
+// EventsImpactSummary previews what cancelling or deleting a set of events would affect
+type EventsImpactSummary struct {
+	Events        []EventImpact `json:"events"`
+	ActiveTickets int           `json:"active_tickets"`
+	RefundTotal   float64       `json:"refund_total"`
+	MissingIDs    []string      `json:"missing_ids,omitempty"` // Unknown or already deleted events
+}
+
+type EventImpact struct {
+	EventID       string      `json:"event_id"`
+	Name          string      `json:"name"`
+	Status        EventStatus `json:"status"`
+	ActiveTickets int         `json:"active_tickets"` // Tickets a cancellation would cancel, comps included
+	SeatsHeld     int         `json:"seats_held"`
+	RefundTotal   float64     `json:"refund_total"` // Paid by holders of the active tickets
+	Cancellable   bool        `json:"cancellable"`
+	Deletable     bool        `json:"deletable"` // Only events that never sold a ticket can be deleted
+}
+
+// EventTicketTotals aggregates an event's active tickets
+type EventTicketTotals struct {
+	EventID     string
+	Tickets     int
+	Seats       int
+	RefundTotal float64
+}
+
+type TicketStatusCount struct {
+	Status TicketStatus `json:"status"`
+	Count  int          `json:"count"`
+}
+
+type CancellationReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+type CategoryReport struct {
+	Category    string  `json:"category"`
+	TicketsSold int     `json:"tickets_sold"`
+	Revenue     float64 `json:"revenue"`
+}
+
+type LocationReport struct {
+	Location    string  `json:"location"`
+	TicketsSold int     `json:"tickets_sold"`
+	Revenue     float64 `json:"revenue"`
+}
+
+// ServerMeta exposes server time and client-facing limits
+type ServerMeta struct {
+	ServerTime                time.Time `json:"server_time"`
+	PurchaseCutoffMinutes     int       `json:"purchase_cutoff_minutes"`
+	CancellationCutoffMinutes int       `json:"cancellation_cutoff_minutes"`
+	CheckInOpensMinutesBefore int       `json:"check_in_opens_minutes_before"` // 0 when check-in has no earliest time
+	CheckInGraceMinutes       int       `json:"check_in_grace_minutes"`        // 0 when check-in never closes, events may override it
+	MaxTicketsPerPurchase     int       `json:"max_tickets_per_purchase"`
+	ServiceFeePercent         float64   `json:"service_fee_percent"`
+	ServiceFeeFlat            float64   `json:"service_fee_flat"`
+	DefaultPageLimit          int       `json:"default_page_limit"`
+	MaxPageLimit              int       `json:"max_page_limit"`
 }
 
 type DateRangeFilter struct {
-	StartDate *time.Time `form:"start_date" json:"start_date"`
-	EndDate   *time.Time `form:"end_date" json:"end_date"`
+	StartDate *time.Time `form:"-" json:"start_date"` // Parsed by the controller, see bindDateQueries
+	EndDate   *time.Time `form:"-" json:"end_date"`
 } 
\ No newline at end of file
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,13 +23,31 @@ type Ticket struct {
 	UserID       string         `json:"user_id" gorm:"type:varchar(36);not null"`
 	EventID      string         `json:"event_id" gorm:"type:varchar(36);not null"`
 	Quantity     int            `json:"quantity" gorm:"not null;default:1" validate:"required,min=1"`
-	TotalPrice   float64        `json:"total_price" gorm:"not null"`
-	Status       TicketStatus   `json:"status" gorm:"type:enum('active','used','cancelled','expired');default:'active'"`
+	TotalPrice   float64        `json:"total_price" gorm:"not null"` // Includes tax and fees
+	TaxAmount    float64        `json:"tax_amount" gorm:"not null;default:0"`
+	Fee          float64        `json:"fee" gorm:"not null;default:0"`
+	Status       TicketStatus   `json:"status" gorm:"type:varchar(20);default:'active';check:chk_tickets_status,status IN ('active','used','cancelled','expired')"`
+	StatusReason string         `json:"status_reason,omitempty" gorm:"type:varchar(255)"`
+	IsComp       bool           `json:"is_comp" gorm:"not null;default:false"`
+	IssuedBy     string         `json:"issued_by,omitempty" gorm:"type:varchar(36)"`    // Admin who issued a comp ticket
+	CancelledBy  string         `json:"cancelled_by,omitempty" gorm:"type:varchar(36)"` // Admin who force-cancelled the ticket
 	PurchaseDate time.Time      `json:"purchase_date" gorm:"not null"`
+	CheckedInAt  *time.Time     `json:"checked_in_at,omitempty"` // Set when the ticket is marked used
+	RemindedAt   *time.Time     `json:"reminded_at,omitempty"`   // Set once the event reminder was sent
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
-	
+
+	// Address purchase notifications go to when buying for someone else
+	DeliveryEmail string `json:"delivery_email,omitempty" gorm:"type:varchar(255)"`
+
+	// Share of the price kept when the holder cancelled; the rest was refunded
+	CancellationFee float64 `json:"cancellation_fee" gorm:"not null;default:0"`
+
+	// Computed from the event date and cancellation cutoff, not stored
+	IsRefundable   bool       `json:"is_refundable" gorm:"-"`
+	CancelDeadline *time.Time `json:"cancel_deadline,omitempty" gorm:"-"`
+
 	// Relationships
 	User  User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Event Event `json:"event,omitempty" gorm:"foreignKey:EventID"`
@@ -43,23 +63,194 @@ func (t *Ticket) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsValid reports whether s is one of the known ticket statuses
+func (s TicketStatus) IsValid() bool {
+	switch s {
+	case TicketStatusActive, TicketStatusUsed, TicketStatusCancelled, TicketStatusExpired:
+		return true
+	}
+	return false
+}
+
+// RefundAmount is what a cancelled ticket's holder got back, 0 for other tickets
+func (t *Ticket) RefundAmount() float64 {
+	if t.Status != TicketStatusCancelled {
+		return 0
+	}
+	return t.TotalPrice - t.CancellationFee
+}
+
 func (t *Ticket) CanBeCancelled() bool {
 	return t.Status == TicketStatusActive
 }
 
+// ApplyCancellationWindow fills the computed refund fields for an event starting at eventDate
+func (t *Ticket) ApplyCancellationWindow(eventDate time.Time, cutoff time.Duration, now time.Time) {
+	if eventDate.IsZero() {
+		return
+	}
+
+	deadline := eventDate.Add(-cutoff)
+	t.CancelDeadline = &deadline
+	t.IsRefundable = t.CanBeCancelled() && !now.After(deadline)
+}
+
 type BuyTicketRequest struct {
+	EventID       string `json:"event_id" validate:"required"`
+	Quantity      int    `json:"quantity" validate:"required,min=1,max=1000"`
+	DeliveryEmail string `json:"delivery_email,omitempty" validate:"omitempty,email"` // Defaults to the buyer's account email
+}
+
+// Validate checks the filter for unknown statuses and contradictory ranges
+func (f *TicketFilter) Validate() error {
+	if f.Status != "" && !TicketStatus(f.Status).IsValid() {
+		return errors.New("invalid status filter")
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil && *f.MinTotal > *f.MaxTotal {
+		return errors.New("min total cannot exceed max total")
+	}
+	return nil
+}
+
+// IssueCompTicketRequest issues a complimentary ticket to a user on an admin's behalf
+type IssueCompTicketRequest struct {
+	UserID   string `json:"user_id" validate:"required"`
 	EventID  string `json:"event_id" validate:"required"`
-	Quantity int    `json:"quantity" validate:"required,min=1"`
+	Quantity int    `json:"quantity" validate:"required,min=1,max=1000"`
+	Reason   string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// TicketQuote is the price breakdown of a purchase, computed without reserving tickets
+type TicketQuote struct {
+	EventID    string  `json:"event_id"`
+	Quantity   int     `json:"quantity"`
+	UnitPrice  float64 `json:"unit_price"`
+	Subtotal   float64 `json:"subtotal"`
+	TaxRate    float64 `json:"tax_rate"`
+	TaxAmount  float64 `json:"tax_amount"`
+	Fee        float64 `json:"fee"`
+	TotalPrice float64 `json:"total_price"`
+	Available  int     `json:"available"`
 }
 
 type TicketFilter struct {
 	UserID    string `form:"user_id"`
 	EventID   string `form:"event_id"`
 	Status    string `form:"status"`
-	StartDate *time.Time `form:"start_date"`
-	EndDate   *time.Time `form:"end_date"`
+	StartDate *time.Time `form:"-"` // Parsed by the controller, see bindDateQueries
+	EndDate   *time.Time `form:"-"`
+
+	// IncludeCancelled returns cancelled tickets too when no explicit status is requested
+	IncludeCancelled bool `form:"include_cancelled"`
+
+	// IsComp limits results to complimentary (true) or paid (false) tickets
+	IsComp *bool `form:"is_comp"`
+
+	// MinTotal and MaxTotal bound the ticket's total_price
+	MinTotal *float64 `form:"min_total"`
+	MaxTotal *float64 `form:"max_total"`
+
+	// Category limits results to events in this category, taken from the route
+	Category string `form:"-"`
+}
+
+// TicketFields limits ticket listings to these comma separated fields, e.g. "id,status,event"
+type TicketFields struct {
+	Fields string `form:"fields"`
+}
+
+// ticketFieldColumns is the allowlist of stored fields that may be requested through TicketFields
+var ticketFieldColumns = map[string]string{
+	"id":             "id",
+	"user_id":        "user_id",
+	"event_id":       "event_id",
+	"quantity":       "quantity",
+	"total_price":    "total_price",
+	"tax_amount":     "tax_amount",
+	"fee":            "fee",
+	"status":         "status",
+	"status_reason":  "status_reason",
+	"is_comp":        "is_comp",
+	"issued_by":      "issued_by",
+	"cancelled_by":   "cancelled_by",
+	"purchase_date":  "purchase_date",
+	"checked_in_at":  "checked_in_at",
+	"reminded_at":    "reminded_at",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
+	"delivery_email": "delivery_email",
+
+	"cancellation_fee": "cancellation_fee",
+}
+
+// ticketFieldRelations lists the fields served from a preloaded relation, with the
+// columns the relation or the computed refund fields need
+var ticketFieldRelations = map[string]struct {
+	relation string
+	columns  []string
+}{
+	"user":            {"User", []string{"user_id"}},
+	"event":           {"Event", []string{"event_id"}},
+	"is_refundable":   {"Event", []string{"event_id", "status"}},
+	"cancel_deadline": {"Event", []string{"event_id"}},
+}
+
+// FieldList returns the requested response fields, empty when full tickets are wanted
+func (f *TicketFields) FieldList() []string {
+	if f == nil {
+		return nil
+	}
+	return splitCommaList(f.Fields)
+}
+
+// Columns maps the requested fields to columns and relations to preload, reporting
+// false for unknown fields. Relations are only listed when a field needs them.
+func (f *TicketFields) Columns() ([]string, []string, bool) {
+	var columns, relations []string
+	seenColumns := make(map[string]bool)
+	seenRelations := make(map[string]bool)
+	addColumn := func(column string) {
+		if !seenColumns[column] {
+			seenColumns[column] = true
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range f.FieldList() {
+		if column, ok := ticketFieldColumns[field]; ok {
+			addColumn(column)
+			continue
+		}
+
+		rel, ok := ticketFieldRelations[field]
+		if !ok {
+			return nil, nil, false
+		}
+		for _, column := range rel.columns {
+			addColumn(column)
+		}
+		if !seenRelations[rel.relation] {
+			seenRelations[rel.relation] = true
+			relations = append(relations, rel.relation)
+		}
+	}
+	return columns, relations, true
+}
+
+// ProjectTickets reduces each ticket to the given JSON fields for slim list payloads
+func ProjectTickets(tickets []Ticket, fields []string) ([]map[string]json.RawMessage, error) {
+	return projectFields(tickets, fields)
 }
 
 type UpdateTicketStatusRequest struct {
-	Status TicketStatus `json:"status" validate:"required,oneof=cancelled used"`
+	Status TicketStatus `json:"status" validate:"required,oneof=cancelled used expired"`
+	Reason string       `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+type CancelTicketRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+type ForceCancelTicketRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
 } 
\ No newline at end of file
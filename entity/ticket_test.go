@@ -0,0 +1,154 @@
+package entity
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// checkValues returns the values listed in a field's gorm check constraint
+func checkValues(t *testing.T, model interface{}, field string) []string {
+	t.Helper()
+
+	structField, ok := reflect.TypeOf(model).FieldByName(field)
+	if !ok {
+		t.Fatalf("%T has no field %s", model, field)
+	}
+	list := regexp.MustCompile(`IN \(([^)]*)\)`).FindStringSubmatch(structField.Tag.Get("gorm"))
+	if list == nil {
+		t.Fatalf("%T.%s has no IN check constraint", model, field)
+	}
+
+	var values []string
+	for _, match := range regexp.MustCompile(`'([^']*)'`).FindAllStringSubmatch(list[1], -1) {
+		values = append(values, match[1])
+	}
+	return values
+}
+
+// TestStatusCheckConstraintsMatchIsValid keeps the database constraints and the
+// application's status validation listing the same values
+func TestStatusCheckConstraintsMatchIsValid(t *testing.T) {
+	ticketStatuses := checkValues(t, Ticket{}, "Status")
+	if len(ticketStatuses) != 4 {
+		t.Errorf("ticket status constraint lists %v", ticketStatuses)
+	}
+	for _, status := range ticketStatuses {
+		if !TicketStatus(status).IsValid() {
+			t.Errorf("ticket status %q is allowed by the column but not IsValid", status)
+		}
+	}
+
+	eventStatuses := checkValues(t, Event{}, "Status")
+	if len(eventStatuses) != 5 {
+		t.Errorf("event status constraint lists %v", eventStatuses)
+	}
+	for _, status := range eventStatuses {
+		if !EventStatus(status).IsValid() {
+			t.Errorf("event status %q is allowed by the column but not IsValid", status)
+		}
+	}
+
+	roles := checkValues(t, User{}, "Role")
+	if !reflect.DeepEqual(roles, []string{string(RoleAdmin), string(RoleUser)}) {
+		t.Errorf("role constraint lists %v, want admin and user", roles)
+	}
+}
+
+func TestStatusIsValid(t *testing.T) {
+	for _, status := range []TicketStatus{"", "ACTIVE", "refunded", "active "} {
+		if status.IsValid() {
+			t.Errorf("TicketStatus(%q).IsValid() = true", status)
+		}
+	}
+	for _, status := range []EventStatus{"", "Draft", "archived"} {
+		if status.IsValid() {
+			t.Errorf("EventStatus(%q).IsValid() = true", status)
+		}
+	}
+}
+
+func TestTicketFilterValidate(t *testing.T) {
+	low, high := 5.0, 10.0
+
+	tests := []struct {
+		name    string
+		filter  TicketFilter
+		wantErr string
+	}{
+		{"empty", TicketFilter{}, ""},
+		{"known status", TicketFilter{Status: "expired"}, ""},
+		{"unknown status", TicketFilter{Status: "refunded"}, "invalid status filter"},
+		{"ordered totals", TicketFilter{MinTotal: &low, MaxTotal: &high}, ""},
+		{"equal totals", TicketFilter{MinTotal: &low, MaxTotal: &low}, ""},
+		{"reversed totals", TicketFilter{MinTotal: &high, MaxTotal: &low}, "min total cannot exceed max total"},
+	}
+
+	for _, tt := range tests {
+		err := tt.filter.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTicketFieldsColumns(t *testing.T) {
+	tests := []struct {
+		fields        string
+		wantColumns   []string
+		wantRelations []string
+		wantOK        bool
+	}{
+		{"", nil, nil, true},
+		{"id,status", []string{"id", "status"}, nil, true},
+		{" id , id ,quantity", []string{"id", "quantity"}, nil, true},
+		{"id,user", []string{"id", "user_id"}, []string{"User"}, true},
+		{"user_id,user", []string{"user_id"}, []string{"User"}, true},
+		{"event,is_refundable,cancel_deadline", []string{"event_id", "status"}, []string{"Event"}, true},
+		{"user,event", []string{"user_id", "event_id"}, []string{"User", "Event"}, true},
+		{"cancellation_fee", []string{"cancellation_fee"}, nil, true},
+		{"id,password", nil, nil, false},
+		{"deleted_at", nil, nil, false},
+		{"ID", nil, nil, false},
+		{"id;drop table tickets", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		fields := &TicketFields{Fields: tt.fields}
+		columns, relations, ok := fields.Columns()
+		if ok != tt.wantOK || !reflect.DeepEqual(columns, tt.wantColumns) || !reflect.DeepEqual(relations, tt.wantRelations) {
+			t.Errorf("Columns(%q) = %v, %v, %v; want %v, %v, %v",
+				tt.fields, columns, relations, ok, tt.wantColumns, tt.wantRelations, tt.wantOK)
+		}
+	}
+
+	var none *TicketFields
+	if columns, relations, ok := none.Columns(); columns != nil || relations != nil || !ok {
+		t.Errorf("nil TicketFields.Columns() = %v, %v, %v", columns, relations, ok)
+	}
+}
+
+// TestTicketFieldAllowlistMatchesJSON keeps every requestable field a real JSON field of Ticket
+func TestTicketFieldAllowlistMatchesJSON(t *testing.T) {
+	jsonFields := make(map[string]bool)
+	ticketType := reflect.TypeOf(Ticket{})
+	for i := 0; i < ticketType.NumField(); i++ {
+		name := strings.Split(ticketType.Field(i).Tag.Get("json"), ",")[0]
+		jsonFields[name] = true
+	}
+
+	for field := range ticketFieldColumns {
+		if !jsonFields[field] {
+			t.Errorf("allowlisted field %q is not a JSON field of Ticket", field)
+		}
+	}
+	for field := range ticketFieldRelations {
+		if !jsonFields[field] {
+			t.Errorf("relation field %q is not a JSON field of Ticket", field)
+		}
+	}
+}
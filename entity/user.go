@@ -19,12 +19,20 @@ type User struct {
 	Email     string         `json:"email" gorm:"uniqueIndex;not null" validate:"required,email"`
 	Password  string         `json:"-" gorm:"not null" validate:"required,min=6"`
 	Name      string         `json:"name" gorm:"not null" validate:"required,min=2"`
-	Role      UserRole       `json:"role" gorm:"type:enum('admin','user');default:'user'"`
+	Role      UserRole       `json:"role" gorm:"type:varchar(20);default:'user';check:chk_users_role,role IN ('admin','user')"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
-	
+
+	// A requested email change waits here until the new address is confirmed
+	PendingEmail         string     `json:"pending_email,omitempty" gorm:"type:varchar(255)"`
+	EmailChangeToken     string     `json:"-" gorm:"type:varchar(64);index"` // SHA-256 of the emailed token
+	EmailChangeExpiresAt *time.Time `json:"-"`
+
+	// Active tickets for events yet to start, only filled in for the profile
+	UpcomingTicketsCount *int64 `json:"upcoming_tickets_count,omitempty" gorm:"-"`
+
 	// Relationships
 	Tickets []Ticket `json:"tickets,omitempty" gorm:"foreignKey:UserID"`
 }
@@ -40,6 +48,25 @@ func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+// RefreshToken is a long-lived login credential exchanged for new access tokens. Each
+// use revokes it and issues a successor, so presenting a used token again reveals theft.
+type RefreshToken struct {
+	ID         string     `json:"id" gorm:"type:varchar(36);primary_key"`
+	UserID     string     `json:"user_id" gorm:"type:varchar(36);not null;index"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"` // SHA-256 of the issued token
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy string     `json:"replaced_by,omitempty" gorm:"type:varchar(36)"` // Successor issued when the token was used
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
@@ -51,7 +78,33 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UpdateProfileRequest holds the only profile fields users may change themselves;
+// role and is_active have no field here, so submitting them has no effect
+type UpdateProfileRequest struct {
+	Name  string `json:"name,omitempty" validate:"omitempty,min=2"`
+	Email string `json:"email,omitempty" validate:"omitempty,email"`
+}
+
+// ConfirmEmailChangeRequest carries the token from the confirmation link
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type EmailChangeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// UserFilter narrows the admin user listing
+type UserFilter struct {
+	HasTickets *bool `form:"has_tickets"` // Whether the user ever purchased a ticket
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	User         *User  `json:"user"`
 } 
\ No newline at end of file
@@ -3,11 +3,14 @@ package main
 import (
 	"log"
 	"net/http"
+	"strings"
 	"ticketing-system/config"
 	"ticketing-system/controller"
+	"ticketing-system/entity"
 	"ticketing-system/middleware"
 	"ticketing-system/repository"
 	"ticketing-system/service"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -52,26 +55,117 @@ func main() {
 	eventRepo := repository.NewEventRepository(config.DB)
 	ticketRepo := repository.NewTicketRepository(config.DB)
 
+	passwordHasher, err := service.NewPasswordHasher(config.AppConfig.Password.HashAlgorithm)
+	if err != nil {
+		log.Fatal("Invalid password hash configuration:", err)
+	}
+
+	notifier := service.NewLogNotifier()
+
+	emailTemplates, err := service.LoadEmailTemplates(config.AppConfig.Email.TemplateDir)
+	if err != nil {
+		log.Fatal("Invalid email template configuration:", err)
+	}
+
 	userService := service.NewUserService(
 		userRepo,
+		passwordHasher,
+		notifier,
+		emailTemplates,
+		service.EmailChangeSettings{
+			// The link opens a client page, which POSTs the token to /api/v1/profile/email/confirm
+			ConfirmURL: strings.TrimRight(config.AppConfig.Email.BaseURL, "/") + "/confirm-email?token=",
+			TokenTTL:   config.AppConfig.GetEmailChangeTokenTTL(),
+		},
 		config.AppConfig.JWT.Secret,
 		config.AppConfig.GetJWTDuration(),
+		config.AppConfig.GetJWTAdminDuration(),
+		config.AppConfig.GetJWTRefreshDuration(),
+	)
+	activeEventsCache := service.NewActiveEventsCache(config.AppConfig.GetActiveEventsCacheTTL())
+
+	eventService := service.NewEventService(
+		eventRepo,
+		config.DB,
+		entity.EventStatus(config.AppConfig.Event.DefaultStatus),
+		service.EventNameRules{
+			MaxLength:    config.AppConfig.Event.NameMaxLength,
+			BlockedWords: config.AppConfig.GetEventNameBlockedWords(),
+		},
+		config.AppConfig.GetEventTrendingWindow(),
+		config.AppConfig.GetEventMaxAdvance(),
+		notifier,
+		emailTemplates,
+		activeEventsCache,
 	)
-	eventService := service.NewEventService(eventRepo)
-	ticketService := service.NewTicketService(ticketRepo, eventRepo, userRepo, config.DB)
+
+	purchaseIsolation, err := service.ParseIsolationLevel(config.AppConfig.Ticket.PurchaseIsolationLevel)
+	if err != nil {
+		log.Fatal("Invalid purchase isolation configuration:", err)
+	}
+
+	cancellationFeeTiers, err := service.ParseCancellationFeeTiers(config.AppConfig.Ticket.CancellationFeeTiers)
+	if err != nil {
+		log.Fatal("Invalid cancellation fee configuration:", err)
+	}
+
+	ticketRules := service.TicketRules{
+		PurchaseCutoff:     time.Duration(config.AppConfig.Ticket.PurchaseCutoffMinutes) * time.Minute,
+		CancellationCutoff: time.Duration(config.AppConfig.Ticket.CancellationCutoffMinutes) * time.Minute,
+		MaxPerPurchase:     config.AppConfig.Ticket.MaxPerPurchase,
+		DuplicateWindow:    time.Duration(config.AppConfig.Ticket.DuplicateWindowSeconds) * time.Second,
+		CheckInOpensBefore: time.Duration(config.AppConfig.Ticket.CheckInOpensMinutesBefore) * time.Minute,
+		CheckInGrace:       time.Duration(config.AppConfig.Ticket.CheckInGraceMinutes) * time.Minute,
+		PurchaseIsolation:  purchaseIsolation,
+
+		CancellationFeeTiers: cancellationFeeTiers,
+	}
+	if config.AppConfig.Ticket.ServiceFeeEnabled {
+		ticketRules.ServiceFeePercent = config.AppConfig.Ticket.ServiceFeePercent
+		ticketRules.ServiceFeeFlat = config.AppConfig.Ticket.ServiceFeeFlat
+	}
+	ticketService := service.NewTicketService(
+		ticketRepo,
+		eventRepo,
+		userRepo,
+		config.DB,
+		notifier,
+		emailTemplates,
+		ticketRules,
+		config.AppConfig.Export.BatchSize,
+		activeEventsCache,
+	)
+
+	if config.AppConfig.Reminder.Enabled {
+		service.StartReminderJob(
+			ticketService,
+			config.AppConfig.GetReminderWindow(),
+			config.AppConfig.GetReminderInterval(),
+		)
+	}
 
 	userController := controller.NewUserController(userService)
 	eventController := controller.NewEventController(eventService)
 	ticketController := controller.NewTicketController(ticketService)
 	reportController := controller.NewReportController(ticketService)
+	metaController := controller.NewMetaController(ticketRules)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(userService)
+	purchaseLimiter := middleware.NewRateLimiter(
+		config.AppConfig.RateLimit.PurchaseLimit,
+		config.AppConfig.GetPurchaseRateWindow(),
+	)
+	purchaseMonitor := middleware.NewPurchaseMonitor(
+		config.AppConfig.RateLimit.SuspiciousPurchaseThreshold,
+		config.AppConfig.GetSuspiciousPurchaseWindow(),
+	)
 
 	// Initialize Gin router
 	r := gin.Default()
 
 	// Global middleware
+	r.Use(middleware.RequestID())
 	r.Use(middleware.CORSMiddleware())
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
@@ -87,19 +181,32 @@ func main() {
 
 	// API routes
 	api := r.Group("/api/v1")
+	api.Use(middleware.MinSearchLength(config.AppConfig.Search.MinQueryLength))
+	api.Use(middleware.NoStore())
 	{
 		// Public routes (no authentication required)
 		public := api.Group("")
+		public.Use(authMiddleware.OptionalAuth())
 		{
 			// Authentication routes
 			public.POST("/register", userController.Register)
 			public.POST("/login", userController.Login)
+			public.POST("/refresh", userController.RefreshToken)
+			public.POST("/profile/email/confirm", userController.ConfirmEmailChange)
 
-			// Public event routes
-			public.GET("/events", eventController.GetAllEvents)
-			public.GET("/events/:id", eventController.GetEventByID)
-			public.GET("/events/active", eventController.GetActiveEvents)
-			public.GET("/events/upcoming", eventController.GetUpcomingEvents)
+			// Server time and client-facing limits
+			public.GET("/meta", metaController.GetMeta)
+
+			// Public event routes, cacheable for anonymous clients
+			listCache := middleware.PublicCache(config.AppConfig.GetEventListCacheMaxAge())
+			detailCache := middleware.PublicCache(config.AppConfig.GetEventDetailCacheMaxAge())
+			public.GET("/events", listCache, eventController.GetAllEvents)
+			public.GET("/events/:id", detailCache, eventController.GetEventByID)
+			public.GET("/events/active", listCache, eventController.GetActiveEvents)
+			public.GET("/events/upcoming", listCache, eventController.GetUpcomingEvents)
+			public.GET("/events/trending", listCache, eventController.GetTrendingEvents)
+			public.GET("/events/shared/:token", eventController.GetSharedEvent)
+			public.POST("/events/batch", eventController.GetEventsByIDs)
 		}
 
 		// Protected routes (authentication required)
@@ -109,12 +216,16 @@ func main() {
 			// User profile routes
 			protected.GET("/profile", userController.GetProfile)
 			protected.PUT("/profile", userController.UpdateProfile)
+			protected.GET("/profile/spending", ticketController.GetMySpending)
+			protected.POST("/profile/email", userController.RequestEmailChange)
 
 			// Ticket routes for authenticated users
-			protected.POST("/tickets", ticketController.BuyTicket)
+			protected.POST("/tickets", purchaseMonitor.Watch(), purchaseLimiter.PerUser(), ticketController.BuyTicket)
+			protected.POST("/tickets/quote", ticketController.QuoteTicket)
 			protected.GET("/tickets/my", ticketController.GetUserTickets)
 			protected.GET("/tickets/:id", ticketController.GetTicketByID)
 			protected.PATCH("/tickets/:id/cancel", ticketController.CancelTicket)
+			protected.GET("/events/:id/my-tickets", ticketController.GetMyEventTickets)
 		}
 
 		// Admin routes (admin access required)
@@ -130,20 +241,54 @@ func main() {
 			admin.POST("/events", eventController.CreateEvent)
 			admin.PUT("/events/:id", eventController.UpdateEvent)
 			admin.DELETE("/events/:id", eventController.DeleteEvent)
+			admin.PATCH("/events/status", eventController.BulkUpdateEventStatus)
+			admin.POST("/events/:id/cancel", eventController.CancelEvent)
+			admin.POST("/events/:id/publish", eventController.PublishEvent)
+			admin.PATCH("/events/:id/sales", eventController.SetEventSales)
+			admin.POST("/events/:id/share-token", eventController.RegenerateShareToken)
+			admin.GET("/events/:id/status-history", eventController.GetEventStatusHistory)
+			admin.GET("/events/deleted", eventController.GetDeletedEvents)
 
 			// Ticket management (admin only)
 			admin.GET("/tickets", ticketController.GetAllTickets)
+			admin.GET("/tickets/export", ticketController.ExportTickets)
 			admin.PATCH("/tickets/:id", ticketController.UpdateTicketStatus)
+			admin.POST("/tickets/comp", ticketController.IssueCompTicket)
+			admin.PATCH("/tickets/:id/force-cancel", ticketController.ForceCancelTicket)
 
 			// Reports (admin only)
 			admin.GET("/reports/summary", reportController.GetSummaryReport)
 			admin.GET("/reports/event/:id", reportController.GetEventReport)
+			admin.GET("/reports/event/:id/detailed", reportController.GetDetailedEventReport)
+			admin.GET("/reports/event/:id/timeline", reportController.GetEventSalesTimeline)
+			admin.GET("/reports/by-category", reportController.GetCategoryReport)
+			admin.GET("/reports/category/:category/tickets", reportController.GetCategoryTickets)
+			admin.GET("/reports/by-location", reportController.GetLocationReport)
+			admin.GET("/reports/refunds/export", reportController.ExportRefunds)
+			admin.POST("/reports/events/impact", reportController.GetEventsImpact)
 		}
 	}
 
 	// Swagger documentation route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Unknown routes and methods use the standard response envelope
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, entity.Response{
+			Success: false,
+			Message: "Route " + c.Request.Method + " " + c.Request.URL.Path + " not found",
+			Error:   "not_found",
+		})
+	})
+	r.NoMethod(func(c *gin.Context) {
+		c.JSON(http.StatusMethodNotAllowed, entity.Response{
+			Success: false,
+			Message: "Method " + c.Request.Method + " not allowed for " + c.Request.URL.Path,
+			Error:   "method_not_allowed",
+		})
+	})
+
 	// Start server
 	port := ":" + config.AppConfig.Server.Port
 	log.Printf("🚀 Server starting on port %s", config.AppConfig.Server.Port)
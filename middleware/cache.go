@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoStore marks responses as uncacheable. It is the default for all API routes,
+// so authenticated and mutating endpoints are never stored by browsers or CDNs.
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Next()
+	}
+}
+
+// PublicCache lets anonymous GET responses be cached for maxAge. Requests carrying
+// credentials may see admin-only data, so they keep no-store. A maxAge of 0 disables caching.
+func PublicCache(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Authorization")
+
+		if maxAge > 0 && c.Request.Method == "GET" && c.GetHeader("Authorization") == "" {
+			c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+		} else {
+			c.Header("Cache-Control", "no-store")
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PurchaseMonitor records users and client IPs making more than threshold purchase
+// attempts within a sliding window. It never blocks a request; suspicious activity is
+// written to the audit log, at most once per key per window.
+type PurchaseMonitor struct {
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+	reported map[string]time.Time
+
+	// When idle keys were last dropped; sweeps run at most once per window
+	swept time.Time
+}
+
+func NewPurchaseMonitor(threshold int, window time.Duration) *PurchaseMonitor {
+	return &PurchaseMonitor{
+		threshold: threshold,
+		window:    window,
+		attempts:  make(map[string][]time.Time),
+		reported:  make(map[string]time.Time),
+	}
+}
+
+// Watch counts purchase attempts per user and per client IP. It should run ahead of
+// the purchase rate limiter so that rejected attempts are counted too.
+func (m *PurchaseMonitor) Watch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A non-positive threshold disables monitoring
+		if m.threshold <= 0 || m.window <= 0 {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		userID, _ := GetCurrentUserID(c)
+		ip := c.ClientIP()
+
+		if userID != "" {
+			if count, report := m.record("user:"+userID, now); report {
+				log.Printf("audit: suspicious purchase activity: user %s made %d purchase attempts within %s (last from ip %s, request %s)",
+					userID, count, m.window, ip, GetRequestID(c))
+			}
+		}
+		if count, report := m.record("ip:"+ip, now); report {
+			log.Printf("audit: suspicious purchase activity: ip %s made %d purchase attempts within %s (last by user %s, request %s)",
+				ip, count, m.window, userID, GetRequestID(c))
+		}
+
+		c.Next()
+	}
+}
+
+// record adds an attempt for key and returns the attempts within the window, reporting
+// whether the threshold is exceeded and key has not already been reported this window
+func (m *PurchaseMonitor) record(key string, now time.Time) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := now.Add(-m.window)
+	if now.Sub(m.swept) >= m.window {
+		m.sweep(cutoff)
+		m.swept = now
+	}
+
+	recent := m.attempts[key][:0]
+	for _, t := range m.attempts[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, now)
+	m.attempts[key] = recent
+
+	if len(recent) <= m.threshold {
+		return len(recent), false
+	}
+	if last, ok := m.reported[key]; ok && last.After(cutoff) {
+		return len(recent), false
+	}
+	m.reported[key] = now
+	return len(recent), true
+}
+
+// sweep drops users and IPs with no attempt or report after cutoff, so keys seen once
+// do not stay in memory. Attempts are appended in order, so the last one is the newest.
+func (m *PurchaseMonitor) sweep(cutoff time.Time) {
+	for key, times := range m.attempts {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(m.attempts, key)
+		}
+	}
+	for key, last := range m.reported {
+		if !last.After(cutoff) {
+			delete(m.reported, key)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPurchaseMonitorDropsIdleKeys(t *testing.T) {
+	m := NewPurchaseMonitor(1, time.Minute)
+	start := time.Now()
+
+	m.record("ip:1.2.3.4", start)
+	if _, report := m.record("ip:1.2.3.4", start.Add(time.Second)); !report {
+		t.Fatal("attempt over the threshold was not reported")
+	}
+	if _, report := m.record("ip:1.2.3.4", start.Add(2*time.Second)); report {
+		t.Fatal("key was reported twice in one window")
+	}
+	m.record("user:a", start)
+
+	// Once the window has passed, the next attempt sweeps keys that went quiet
+	later := start.Add(2 * time.Minute)
+	m.record("user:b", later)
+	if len(m.attempts) != 1 {
+		t.Fatalf("%d attempt keys kept after the sweep, want 1", len(m.attempts))
+	}
+	if len(m.reported) != 0 {
+		t.Fatalf("%d reported keys kept after the sweep, want 0", len(m.reported))
+	}
+
+	// A swept key starts counting again and can be reported again
+	m.record("ip:1.2.3.4", later)
+	if _, report := m.record("ip:1.2.3.4", later.Add(time.Second)); !report {
+		t.Fatal("swept key was not reported again")
+	}
+}
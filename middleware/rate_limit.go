@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"ticketing-system/entity"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter allows at most limit requests per key within a sliding window
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu       sync.Mutex
+	requests map[string][]time.Time
+
+	// When idle keys were last dropped; sweeps run at most once per window
+	swept time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:    limit,
+		window:   window,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// PerUser limits requests keyed on the authenticated user ID, so it must run after AuthRequired
+func (l *RateLimiter) PerUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A non-positive limit disables rate limiting
+		if l.limit <= 0 {
+			c.Next()
+			return
+		}
+
+		userID, exists := GetCurrentUserID(c)
+		if !exists {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter := l.allow(userID, time.Now())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, entity.Response{
+				Success: false,
+				Message: "Too many requests, please try again later",
+				Error:   "rate_limit_exceeded",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow records a request for key and reports whether it fits in the window,
+// returning how long until the oldest request expires when it does not
+func (l *RateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	if now.Sub(l.swept) >= l.window {
+		l.sweep(cutoff)
+		l.swept = now
+	}
+
+	recent := l.requests[key][:0]
+	for _, t := range l.requests[key] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.requests[key] = recent
+		return false, recent[0].Sub(cutoff)
+	}
+
+	l.requests[key] = append(recent, now)
+	return true, 0
+}
+
+// sweep drops keys with no request after cutoff, so users who stop sending requests do
+// not stay in memory. Requests are appended in order, so the last one is the newest.
+func (l *RateLimiter) sweep(cutoff time.Time) {
+	for key, times := range l.requests {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(l.requests, key)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterDropsIdleKeys(t *testing.T) {
+	l := NewRateLimiter(2, time.Minute)
+	start := time.Now()
+
+	l.allow("user-a", start)
+	l.allow("user-b", start)
+	if allowed, _ := l.allow("user-a", start.Add(time.Second)); !allowed {
+		t.Fatal("second request within the limit was rejected")
+	}
+	if allowed, _ := l.allow("user-a", start.Add(2*time.Second)); allowed {
+		t.Fatal("third request within the window was allowed")
+	}
+
+	// Once the window has passed, the next request sweeps keys that went quiet
+	later := start.Add(2 * time.Minute)
+	if allowed, _ := l.allow("user-c", later); !allowed {
+		t.Fatal("request from a new key was rejected")
+	}
+	if len(l.requests) != 1 {
+		t.Fatalf("%d keys kept after the sweep, want 1", len(l.requests))
+	}
+	if _, ok := l.requests["user-c"]; !ok {
+		t.Fatal("active key was swept")
+	}
+	if allowed, _ := l.allow("user-a", later); !allowed {
+		t.Fatal("swept key was still limited")
+	}
+}
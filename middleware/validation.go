@@ -3,8 +3,10 @@ package middleware
 import (
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"ticketing-system/entity"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -93,6 +95,25 @@ func ValidateQuery(obj interface{}) gin.HandlerFunc {
 	}
 }
 
+// MinSearchLength rejects search queries (q) shorter than minLength characters, which would
+// otherwise turn into near full table LIKE scans. A minLength of 0 or less disables the check.
+func MinSearchLength(minLength int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if minLength > 0 && query != "" && utf8.RuneCountInString(query) < minLength {
+			c.JSON(http.StatusBadRequest, entity.Response{
+				Success: false,
+				Message: "Invalid search parameters",
+				Error:   "q must be at least " + strconv.Itoa(minLength) + " characters long",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func formatValidationError(err validator.FieldError) string {
 	field := err.Field()
 	tag := err.Tag()
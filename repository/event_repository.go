@@ -12,15 +12,23 @@ type EventRepository interface {
 	CreateWithTx(tx *gorm.DB, event *entity.Event) error
 	GetByID(id string) (*entity.Event, error)
 	GetByIDWithTx(tx *gorm.DB, id string) (*entity.Event, error)
+	GetByIDs(ids []string) ([]entity.Event, error)
 	GetByName(name string) (*entity.Event, error)
+	GetByShareToken(token string) (*entity.Event, error)
 	Update(event *entity.Event) error
 	UpdateWithTx(tx *gorm.DB, event *entity.Event) error
 	Delete(id string) error
 	GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.EventFilter) ([]entity.Event, int64, error)
 	GetActiveEvents() ([]entity.Event, error)
+	GetDeleted(pagination *entity.Pagination) ([]entity.Event, int64, error)
 	UpdateAvailableTickets(eventID string, quantity int) error
 	UpdateAvailableTicketsWithTx(tx *gorm.DB, eventID string, quantity int) error
+	SetSalesPaused(eventID string, paused bool) error
+	SetShareToken(eventID, token string) error
 	GetUpcomingEvents(limit int) ([]entity.Event, error)
+	GetTrendingEvents(since time.Time, limit int) ([]entity.TrendingEvent, error)
+	CreateStatusChangeWithTx(tx *gorm.DB, change *entity.EventStatusChange) error
+	GetStatusHistory(eventID string) ([]entity.EventStatusChange, error)
 }
 
 type eventRepository struct {
@@ -59,7 +67,22 @@ func (r *eventRepository) GetByIDWithTx(tx *gorm.DB, id string) (*entity.Event,
 
 func (r *eventRepository) GetByName(name string) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.Where("name = ?", name).First(&event).Error
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *eventRepository) GetByIDs(ids []string) ([]entity.Event, error) {
+	events := []entity.Event{}
+	err := r.db.Where("id IN ?", ids).Find(&events).Error
+	return events, err
+}
+
+func (r *eventRepository) GetByShareToken(token string) (*entity.Event, error) {
+	var event entity.Event
+	err := r.db.Where("share_token = ?", token).First(&event).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,10 +102,16 @@ func (r *eventRepository) Delete(id string) error {
 }
 
 func (r *eventRepository) GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.EventFilter) ([]entity.Event, int64, error) {
+	db := fromReplica(r.db)
 	var events []entity.Event
 	var total int64
 
-	query := r.db.Model(&entity.Event{})
+	query := db.Model(&entity.Event{})
+
+	// Soft-deleted events are returned as deletion markers for syncing clients
+	if filter != nil && filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
 
 	// Apply search filter
 	if search != nil && search.Query != "" {
@@ -93,11 +122,18 @@ func (r *eventRepository) GetAll(pagination *entity.Pagination, search *entity.S
 
 	// Apply filters
 	if filter != nil {
-		if filter.Category != "" {
-			query = query.Where("category = ?", filter.Category)
+		// Drafts are hidden unless the caller may see them
+		if !filter.IncludeDrafts {
+			query = query.Where("status != ?", entity.EventStatusDraft)
 		}
-		if filter.Status != "" {
-			query = query.Where("status = ?", filter.Status)
+		if !filter.IncludePrivate {
+			query = query.Where("visibility = ?", entity.EventVisibilityPublic)
+		}
+		if categories := filter.CategoryList(); len(categories) > 0 {
+			query = query.Where("category IN ?", categories)
+		}
+		if statuses := filter.StatusList(); len(statuses) > 0 {
+			query = query.Where("status IN ?", statuses)
 		}
 		if filter.Location != "" {
 			query = query.Where("location LIKE ?", "%"+filter.Location+"%")
@@ -108,12 +144,22 @@ func (r *eventRepository) GetAll(pagination *entity.Pagination, search *entity.S
 		if filter.MaxPrice != nil {
 			query = query.Where("price <= ?", *filter.MaxPrice)
 		}
+		if filter.MinAvailable != nil {
+			query = query.Where("available >= ?", *filter.MinAvailable)
+		}
 		if filter.StartDate != nil {
 			query = query.Where("event_date >= ?", *filter.StartDate)
 		}
 		if filter.EndDate != nil {
 			query = query.Where("event_date <= ?", *filter.EndDate)
 		}
+		if filter.UpdatedSince != nil {
+			query = query.Where("updated_at >= ? OR deleted_at >= ?", *filter.UpdatedSince, *filter.UpdatedSince)
+		}
+		if filter.CreatedByEmail != "" {
+			creators := db.Model(&entity.User{}).Select("id").Where("LOWER(email) = LOWER(?)", filter.CreatedByEmail)
+			query = query.Where("created_by IN (?)", creators)
+		}
 	}
 
 	// Count total records
@@ -121,21 +167,45 @@ func (r *eventRepository) GetAll(pagination *entity.Pagination, search *entity.S
 		return nil, 0, err
 	}
 
+	// Load only the requested columns, after counting so the count stays a plain COUNT(*)
+	if filter != nil {
+		if columns, ok := filter.FieldColumns(); ok && len(columns) > 0 {
+			query = query.Select(columns)
+		}
+	}
+
 	// Apply pagination and ordering
 	if pagination != nil {
 		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
 	}
 	
-	query = query.Order("created_at DESC")
+	query = query.Order("created_at DESC, id DESC")
 
 	err := query.Find(&events).Error
 	return events, total, err
 }
 
+// GetDeleted lists soft-deleted events, most recently deleted first
+func (r *eventRepository) GetDeleted(pagination *entity.Pagination) ([]entity.Event, int64, error) {
+	db := fromReplica(r.db)
+	var events []entity.Event
+	var total int64
+
+	query := db.Model(&entity.Event{}).Unscoped().Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit()).
+		Order("deleted_at DESC, id DESC").
+		Find(&events).Error
+	return events, total, err
+}
+
 func (r *eventRepository) GetActiveEvents() ([]entity.Event, error) {
 	var events []entity.Event
-	err := r.db.Where("status = ? AND available > 0", entity.EventStatusActive).
-		Order("event_date ASC").
+	err := r.db.Where("status = ? AND visibility = ? AND available > 0", entity.EventStatusActive, entity.EventVisibilityPublic).
+		Order("event_date ASC, id ASC").
 		Find(&events).Error
 	return events, err
 }
@@ -152,11 +222,83 @@ func (r *eventRepository) UpdateAvailableTicketsWithTx(tx *gorm.DB, eventID stri
 		UpdateColumn("available", gorm.Expr("available - ?", quantity)).Error
 }
 
+// SetSalesPaused updates only the pause flag, so concurrent purchases changing availability are not overwritten
+func (r *eventRepository) SetSalesPaused(eventID string, paused bool) error {
+	return r.db.Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		Update("sales_paused", paused).Error
+}
+
+// SetShareToken updates only the share token, so concurrent purchases and status changes are not overwritten
+func (r *eventRepository) SetShareToken(eventID, token string) error {
+	return r.db.Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		Update("share_token", token).Error
+}
+
 func (r *eventRepository) GetUpcomingEvents(limit int) ([]entity.Event, error) {
 	var events []entity.Event
-	err := r.db.Where("status = ? AND event_date > ?", entity.EventStatusActive, time.Now()).
-		Order("event_date ASC").
+	err := r.db.Where("status = ? AND visibility = ? AND event_date > ?", entity.EventStatusActive, entity.EventVisibilityPublic, time.Now()).
+		Order("event_date ASC, id ASC").
 		Limit(limit).
 		Find(&events).Error
 	return events, err
-} 
\ No newline at end of file
+}
+
+// GetTrendingEvents ranks active, public, upcoming events that are not sold out by the
+// paid tickets sold since the given time. Events without sales in the window are left out.
+func (r *eventRepository) GetTrendingEvents(since time.Time, limit int) ([]entity.TrendingEvent, error) {
+	type eventSales struct {
+		EventID string
+		Sold    int
+	}
+
+	db := fromReplica(r.db)
+	var sales []eventSales
+	err := db.Model(&entity.Ticket{}).
+		Select("tickets.event_id, SUM(tickets.quantity) AS sold").
+		Joins("JOIN events ON events.id = tickets.event_id AND events.deleted_at IS NULL").
+		Where("tickets.purchase_date >= ? AND tickets.status <> ? AND tickets.is_comp = ?", since, entity.TicketStatusCancelled, false).
+		Where("events.status = ? AND events.visibility = ? AND events.available > 0 AND events.event_date > ?",
+			entity.EventStatusActive, entity.EventVisibilityPublic, time.Now()).
+		Group("tickets.event_id").
+		Order("sold DESC, tickets.event_id ASC").
+		Limit(limit).
+		Scan(&sales).Error
+	if err != nil || len(sales) == 0 {
+		return []entity.TrendingEvent{}, err
+	}
+
+	ids := make([]string, len(sales))
+	for i, sale := range sales {
+		ids[i] = sale.EventID
+	}
+	var events []entity.Event
+	if err := db.Where("id IN ?", ids).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]entity.Event, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+
+	trending := make([]entity.TrendingEvent, 0, len(sales))
+	for _, sale := range sales {
+		if event, ok := byID[sale.EventID]; ok {
+			trending = append(trending, entity.TrendingEvent{Event: event, RecentTicketsSold: sale.Sold})
+		}
+	}
+	return trending, nil
+}
+
+func (r *eventRepository) CreateStatusChangeWithTx(tx *gorm.DB, change *entity.EventStatusChange) error {
+	return tx.Create(change).Error
+}
+
+func (r *eventRepository) GetStatusHistory(eventID string) ([]entity.EventStatusChange, error) {
+	history := []entity.EventStatusChange{}
+	err := r.db.Where("event_id = ?", eventID).
+		Order("changed_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
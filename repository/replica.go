@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ReadReplica names the resolver a configured read replica is registered under
+const ReadReplica = "read_replica"
+
+// fromReplica sends reads to the read replica when one is configured, otherwise to
+// the primary. Only reports and listings use it, since replicas may lag behind writes.
+func fromReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(ReadReplica)).Session(&gorm.Session{})
+}
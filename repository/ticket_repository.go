@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"ticketing-system/entity"
 	"time"
 
@@ -15,13 +16,26 @@ type TicketRepository interface {
 	Update(ticket *entity.Ticket) error
 	UpdateWithTx(tx *gorm.DB, ticket *entity.Ticket) error
 	Delete(id string) error
-	GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter) ([]entity.Ticket, int64, error)
-	GetByUserID(userID string, pagination *entity.Pagination) ([]entity.Ticket, int64, error)
+	GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter, fields *entity.TicketFields) ([]entity.Ticket, int64, error)
+	FindInBatches(search *entity.Search, filter *entity.TicketFilter, batchSize int, fn func(tickets []entity.Ticket) error) error
+	FindRefundsInBatches(filter *entity.DateRangeFilter, batchSize int, fn func(tickets []entity.Ticket) error) error
+	GetByUserID(userID, eventID string, pagination *entity.Pagination, fields *entity.TicketFields) ([]entity.Ticket, int64, error)
 	GetByEventID(eventID string, pagination *entity.Pagination) ([]entity.Ticket, int64, error)
+	GetActiveByUserAndEvent(userID, eventID string) ([]entity.Ticket, error)
+	GetDueForReminder(from, until time.Time) ([]entity.Ticket, error)
+	MarkReminded(id string, at time.Time) (bool, error)
 	GetTicketStats() (*entity.ReportSummary, error)
 	GetEventReport(eventID string) (*entity.EventReport, error)
+	GetEventRefundTotals(eventID string) (gross, refunds float64, err error)
+	GetEventStatusBreakdown(eventID string) ([]entity.TicketStatusCount, error)
+	GetEventSalesTimeline(eventID, interval string) ([]entity.SalesTimelinePoint, error)
+	GetActiveTicketTotals(eventIDs []string) ([]entity.EventTicketTotals, error)
+	GetUserSpendingByStatus(userID string) ([]entity.StatusSpending, error)
+	GetUserSpendingByEvent(userID string) ([]entity.EventSpending, error)
 	GetRevenueByDateRange(startDate, endDate time.Time) (float64, error)
 	GetTicketsSoldByDateRange(startDate, endDate time.Time) (int, error)
+	GetReportByCategory(filter *entity.DateRangeFilter) ([]entity.CategoryReport, error)
+	GetReportByLocation(pagination *entity.Pagination, filter *entity.DateRangeFilter) ([]entity.LocationReport, int64, error)
 }
 
 type ticketRepository struct {
@@ -70,73 +84,164 @@ func (r *ticketRepository) Delete(id string) error {
 	return r.db.Delete(&entity.Ticket{}, "id = ?", id).Error
 }
 
-func (r *ticketRepository) GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter) ([]entity.Ticket, int64, error) {
+func (r *ticketRepository) GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter, fields *entity.TicketFields) ([]entity.Ticket, int64, error) {
+	db := fromReplica(r.db)
 	var tickets []entity.Ticket
 	var total int64
 
-	query := r.db.Model(&entity.Ticket{}).Preload("User").Preload("Event")
+	query := applyTicketFilters(db.Model(&entity.Ticket{}), search, filter)
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = selectTicketFields(query, fields, "User", "Event")
+
+	// Apply pagination and ordering
+	if pagination != nil {
+		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
+	}
+	
+	query = query.Order("tickets.created_at DESC, tickets.id DESC")
+
+	err := query.Find(&tickets).Error
+	return tickets, total, err
+}
+
+func (r *ticketRepository) FindInBatches(search *entity.Search, filter *entity.TicketFilter, batchSize int, fn func(tickets []entity.Ticket) error) error {
+	db := fromReplica(r.db)
+	var tickets []entity.Ticket
+
+	query := applyTicketFilters(db.Model(&entity.Ticket{}).Preload("User").Preload("Event"), search, filter)
+
+	return query.FindInBatches(&tickets, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(tickets)
+	}).Error
+}
+
+// FindRefundsInBatches streams cancelled paid tickets, whose price was refunded. Tickets
+// have no cancellation timestamp and cannot change once cancelled, so updated_at is
+// used as the refund time and for the date range. Deleted users and events are kept
+// so the export reconciles with past payouts.
+func (r *ticketRepository) FindRefundsInBatches(filter *entity.DateRangeFilter, batchSize int, fn func(tickets []entity.Ticket) error) error {
+	db := fromReplica(r.db)
+	var tickets []entity.Ticket
+
+	unscoped := func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }
+	query := db.Model(&entity.Ticket{}).Preload("User", unscoped).Preload("Event", unscoped).
+		Where("status = ? AND is_comp = ?", entity.TicketStatusCancelled, false)
+	if filter != nil && filter.StartDate != nil {
+		query = query.Where("updated_at >= ?", *filter.StartDate)
+	}
+	if filter != nil && filter.EndDate != nil {
+		query = query.Where("updated_at <= ?", *filter.EndDate)
+	}
+
+	return query.FindInBatches(&tickets, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(tickets)
+	}).Error
+}
+
+// applyTicketFilters adds the search and filter conditions shared by ticket listings and exports
+func applyTicketFilters(query *gorm.DB, search *entity.Search, filter *entity.TicketFilter) *gorm.DB {
+	searching := search != nil && search.Query != ""
+
+	// Search and category filters both need the event, so join it only once
+	if searching || (filter != nil && filter.Category != "") {
+		query = query.Joins("LEFT JOIN events ON tickets.event_id = events.id")
+	}
 
 	// Apply search filter
-	if search != nil && search.Query != "" {
+	if searching {
 		searchQuery := "%" + search.Query + "%"
 		query = query.Joins("LEFT JOIN users ON tickets.user_id = users.id").
-			Joins("LEFT JOIN events ON tickets.event_id = events.id").
 			Where("users.name LIKE ? OR users.email LIKE ? OR events.name LIKE ?", 
 				searchQuery, searchQuery, searchQuery)
 	}
 
-	// Apply filters
+	// Apply filters, qualifying columns that also exist on joined tables
 	if filter != nil {
 		if filter.UserID != "" {
-			query = query.Where("user_id = ?", filter.UserID)
+			query = query.Where("tickets.user_id = ?", filter.UserID)
 		}
 		if filter.EventID != "" {
-			query = query.Where("event_id = ?", filter.EventID)
+			query = query.Where("tickets.event_id = ?", filter.EventID)
 		}
 		if filter.Status != "" {
-			query = query.Where("status = ?", filter.Status)
+			query = query.Where("tickets.status = ?", filter.Status)
+		} else if !filter.IncludeCancelled {
+			query = query.Where("tickets.status != ?", entity.TicketStatusCancelled)
 		}
 		if filter.StartDate != nil {
-			query = query.Where("purchase_date >= ?", *filter.StartDate)
+			query = query.Where("tickets.purchase_date >= ?", *filter.StartDate)
 		}
 		if filter.EndDate != nil {
-			query = query.Where("purchase_date <= ?", *filter.EndDate)
+			query = query.Where("tickets.purchase_date <= ?", *filter.EndDate)
+		}
+		if filter.IsComp != nil {
+			query = query.Where("tickets.is_comp = ?", *filter.IsComp)
+		}
+		if filter.MinTotal != nil {
+			query = query.Where("tickets.total_price >= ?", *filter.MinTotal)
+		}
+		if filter.MaxTotal != nil {
+			query = query.Where("tickets.total_price <= ?", *filter.MaxTotal)
+		}
+		if filter.Category != "" {
+			query = query.Where("events.category = ?", filter.Category)
 		}
 	}
 
-	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+	return query
+}
 
-	// Apply pagination and ordering
-	if pagination != nil {
-		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
+// selectTicketFields loads only the requested columns and the relations they need, or
+// full tickets with the default relations when no fields were requested. It is applied
+// after counting so the count stays a plain COUNT(*).
+func selectTicketFields(query *gorm.DB, fields *entity.TicketFields, defaultRelations ...string) *gorm.DB {
+	columns, relations, _ := fields.Columns()
+	if len(columns) == 0 {
+		relations = defaultRelations
+	} else {
+		// Qualified, since listings may join users and events
+		selected := make([]string, 0, len(columns))
+		for _, column := range columns {
+			selected = append(selected, "tickets."+column)
+		}
+		query = query.Select(selected)
 	}
-	
-	query = query.Order("created_at DESC")
 
-	err := query.Find(&tickets).Error
-	return tickets, total, err
+	for _, relation := range relations {
+		query = query.Preload(relation)
+	}
+	return query
 }
 
-func (r *ticketRepository) GetByUserID(userID string, pagination *entity.Pagination) ([]entity.Ticket, int64, error) {
+func (r *ticketRepository) GetByUserID(userID, eventID string, pagination *entity.Pagination, fields *entity.TicketFields) ([]entity.Ticket, int64, error) {
 	var tickets []entity.Ticket
 	var total int64
 
-	query := r.db.Model(&entity.Ticket{}).Preload("Event").Where("user_id = ?", userID)
+	query := r.db.Model(&entity.Ticket{}).Where("user_id = ?", userID)
+
+	// Optionally scope to a single event
+	if eventID != "" {
+		query = query.Where("event_id = ?", eventID)
+	}
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	query = selectTicketFields(query, fields, "Event")
+
 	// Apply pagination
 	if pagination != nil {
 		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
 	}
 
-	err := query.Order("created_at DESC").Find(&tickets).Error
+	err := query.Order("created_at DESC, id DESC").Find(&tickets).Error
 	return tickets, total, err
 }
 
@@ -156,45 +261,98 @@ func (r *ticketRepository) GetByEventID(eventID string, pagination *entity.Pagin
 		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
 	}
 
-	err := query.Order("created_at DESC").Find(&tickets).Error
+	err := query.Order("created_at DESC, id DESC").Find(&tickets).Error
 	return tickets, total, err
 }
 
+func (r *ticketRepository) GetActiveByUserAndEvent(userID, eventID string) ([]entity.Ticket, error) {
+	tickets := []entity.Ticket{}
+	err := r.db.Where("user_id = ? AND event_id = ? AND status = ?", userID, eventID, entity.TicketStatusActive).
+		Order("created_at DESC, id DESC").
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// GetDueForReminder returns active tickets not yet reminded whose active event starts between from and until
+func (r *ticketRepository) GetDueForReminder(from, until time.Time) ([]entity.Ticket, error) {
+	tickets := []entity.Ticket{}
+	err := r.db.Model(&entity.Ticket{}).Preload("User").Preload("Event").
+		Joins("JOIN events ON tickets.event_id = events.id").
+		Where("tickets.status = ? AND tickets.reminded_at IS NULL", entity.TicketStatusActive).
+		Where("events.status = ? AND events.event_date BETWEEN ? AND ?", entity.EventStatusActive, from, until).
+		Order("events.event_date ASC, tickets.id ASC").
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// MarkReminded claims a ticket's reminder, reporting false if another run already sent it
+func (r *ticketRepository) MarkReminded(id string, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND reminded_at IS NULL", id).
+		UpdateColumn("reminded_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *ticketRepository) GetTicketStats() (*entity.ReportSummary, error) {
+	db := fromReplica(r.db)
 	var summary entity.ReportSummary
 
 	// Get total tickets sold
 	var totalTickets int64
-	if err := r.db.Model(&entity.Ticket{}).Where("status != ?", entity.TicketStatusCancelled).Count(&totalTickets).Error; err != nil {
+	if err := db.Model(&entity.Ticket{}).Where("status != ?", entity.TicketStatusCancelled).Count(&totalTickets).Error; err != nil {
 		return nil, err
 	}
 	summary.TotalTicketsSold = int(totalTickets)
 
-	// Get total revenue
-	var totalRevenue float64
-	if err := r.db.Model(&entity.Ticket{}).Where("status != ?", entity.TicketStatusCancelled).
-		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&totalRevenue); err != nil {
+	// Split sold tickets into comps and paid sales
+	var compTickets int64
+	if err := db.Model(&entity.Ticket{}).Where("status != ? AND is_comp = ?", entity.TicketStatusCancelled, true).Count(&compTickets).Error; err != nil {
+		return nil, err
+	}
+	summary.CompTickets = int(compTickets)
+	summary.PaidTickets = int(totalTickets - compTickets)
+
+	// Get total revenue and the tax and fees included in it
+	var totalRevenue, taxCollected, feesCollected float64
+	if err := db.Model(&entity.Ticket{}).Where("status != ? AND is_comp = ?", entity.TicketStatusCancelled, false).
+		Select("COALESCE(SUM(total_price), 0), COALESCE(SUM(tax_amount), 0), COALESCE(SUM(fee), 0)").
+		Row().Scan(&totalRevenue, &taxCollected, &feesCollected); err != nil {
+		return nil, err
+	}
+
+	// Cancellation fees kept from cancelled tickets are revenue too, neither face value nor service fee
+	var cancellationFees float64
+	if err := db.Model(&entity.Ticket{}).Where("status = ? AND is_comp = ?", entity.TicketStatusCancelled, false).
+		Select("COALESCE(SUM(cancellation_fee), 0)").
+		Row().Scan(&cancellationFees); err != nil {
 		return nil, err
 	}
+
+	totalRevenue += cancellationFees
 	summary.TotalRevenue = totalRevenue
+	summary.RevenueBeforeTax = totalRevenue - taxCollected
+	summary.TaxCollected = taxCollected
+	summary.FaceValue = totalRevenue - taxCollected - feesCollected - cancellationFees
+	summary.FeesCollected = feesCollected
+	summary.CancellationFees = cancellationFees
 
 	// Get total events
 	var totalEvents int64
-	if err := r.db.Model(&entity.Event{}).Count(&totalEvents).Error; err != nil {
+	if err := db.Model(&entity.Event{}).Count(&totalEvents).Error; err != nil {
 		return nil, err
 	}
 	summary.TotalEvents = int(totalEvents)
 
 	// Get active events
 	var activeEvents int64
-	if err := r.db.Model(&entity.Event{}).Where("status = ?", entity.EventStatusActive).Count(&activeEvents).Error; err != nil {
+	if err := db.Model(&entity.Event{}).Where("status = ?", entity.EventStatusActive).Count(&activeEvents).Error; err != nil {
 		return nil, err
 	}
 	summary.ActiveEvents = int(activeEvents)
 
 	// Get total users
 	var totalUsers int64
-	if err := r.db.Model(&entity.User{}).Count(&totalUsers).Error; err != nil {
+	if err := db.Model(&entity.User{}).Count(&totalUsers).Error; err != nil {
 		return nil, err
 	}
 	summary.TotalUsers = int(totalUsers)
@@ -205,24 +363,62 @@ func (r *ticketRepository) GetTicketStats() (*entity.ReportSummary, error) {
 }
 
 func (r *ticketRepository) GetEventReport(eventID string) (*entity.EventReport, error) {
+	db := fromReplica(r.db)
 	var report entity.EventReport
 
 	// Get event details
 	var event entity.Event
-	if err := r.db.Where("id = ?", eventID).First(&event).Error; err != nil {
+	if err := db.Where("id = ?", eventID).First(&event).Error; err != nil {
 		return nil, err
 	}
 
 	// Get tickets sold count
 	var ticketsSold int64
-	if err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ?", eventID, entity.TicketStatusCancelled).Count(&ticketsSold).Error; err != nil {
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ?", eventID, entity.TicketStatusCancelled).Count(&ticketsSold).Error; err != nil {
 		return nil, err
 	}
 
-	// Get total revenue
-	var revenue float64
-	if err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ?", eventID, entity.TicketStatusCancelled).
-		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&revenue); err != nil {
+	var compTickets int64
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ? AND is_comp = ?", eventID, entity.TicketStatusCancelled, true).Count(&compTickets).Error; err != nil {
+		return nil, err
+	}
+
+	// Get total revenue and the tax and fees included in it, from paid tickets only
+	var revenue, taxCollected, feesCollected float64
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ? AND is_comp = ?", eventID, entity.TicketStatusCancelled, false).
+		Select("COALESCE(SUM(total_price), 0), COALESCE(SUM(tax_amount), 0), COALESCE(SUM(fee), 0)").
+		Row().Scan(&revenue, &taxCollected, &feesCollected); err != nil {
+		return nil, err
+	}
+
+	// Add the cancellation fees kept from cancelled tickets, matching the detailed report's net revenue
+	var cancellationFees float64
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ? AND is_comp = ?", eventID, entity.TicketStatusCancelled, false).
+		Select("COALESCE(SUM(cancellation_fee), 0)").
+		Row().Scan(&cancellationFees); err != nil {
+		return nil, err
+	}
+	revenue += cancellationFees
+
+	// Get tickets checked in at the door
+	var checkedIn int64
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status != ? AND checked_in_at IS NOT NULL", eventID, entity.TicketStatusCancelled).Count(&checkedIn).Error; err != nil {
+		return nil, err
+	}
+
+	// Get cancellations grouped by reason
+	var cancelledTickets int64
+	if err := db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", eventID, entity.TicketStatusCancelled).Count(&cancelledTickets).Error; err != nil {
+		return nil, err
+	}
+
+	reasons := []entity.CancellationReasonCount{}
+	if err := db.Model(&entity.Ticket{}).
+		Select("COALESCE(NULLIF(status_reason, ''), 'unspecified') AS reason, COUNT(*) AS count").
+		Where("event_id = ? AND status = ?", eventID, entity.TicketStatusCancelled).
+		Group("reason").
+		Order("count DESC").
+		Scan(&reasons).Error; err != nil {
 		return nil, err
 	}
 
@@ -232,6 +428,12 @@ func (r *ticketRepository) GetEventReport(eventID string) (*entity.EventReport,
 		salesRate = (float64(ticketsSold) / float64(event.Capacity)) * 100
 	}
 
+	// Calculate check-in rate, zero for events without sales
+	checkInRate := float64(0)
+	if ticketsSold > 0 {
+		checkInRate = (float64(checkedIn) / float64(ticketsSold)) * 100
+	}
+
 	report = entity.EventReport{
 		EventID:     event.ID,
 		EventName:   event.Name,
@@ -240,23 +442,192 @@ func (r *ticketRepository) GetEventReport(eventID string) (*entity.EventReport,
 		Capacity:    event.Capacity,
 		Available:   event.Available,
 		SalesRate:   salesRate,
+
+		CheckedIn:   int(checkedIn),
+		CheckInRate: checkInRate,
+
+		PaidTickets:      int(ticketsSold - compTickets),
+		CompTickets:      int(compTickets),
+		RevenueBeforeTax: revenue - taxCollected,
+		TaxCollected:     taxCollected,
+		FaceValue:        revenue - taxCollected - feesCollected - cancellationFees,
+		FeesCollected:    feesCollected,
+
+		CancelledTickets:    int(cancelledTickets),
+		CancellationReasons: reasons,
+
+		CancellationFees: cancellationFees,
 	}
 
 	return &report, nil
 }
 
+// GetEventRefundTotals sums paid tickets for an event including cancelled ones,
+// and separately the cancelled share that was refunded, net of cancellation fees
+func (r *ticketRepository) GetEventRefundTotals(eventID string) (gross, refunds float64, err error) {
+	db := fromReplica(r.db)
+	err = db.Model(&entity.Ticket{}).Where("event_id = ? AND is_comp = ?", eventID, false).
+		Select("COALESCE(SUM(total_price), 0), COALESCE(SUM(CASE WHEN status = ? THEN total_price - cancellation_fee ELSE 0 END), 0)", entity.TicketStatusCancelled).
+		Row().Scan(&gross, &refunds)
+	return gross, refunds, err
+}
+
+func (r *ticketRepository) GetEventStatusBreakdown(eventID string) ([]entity.TicketStatusCount, error) {
+	db := fromReplica(r.db)
+	breakdown := []entity.TicketStatusCount{}
+	err := db.Model(&entity.Ticket{}).
+		Select("status, COUNT(*) AS count").
+		Where("event_id = ?", eventID).
+		Group("status").
+		Order("count DESC").
+		Scan(&breakdown).Error
+	return breakdown, err
+}
+
+func (r *ticketRepository) GetUserSpendingByStatus(userID string) ([]entity.StatusSpending, error) {
+	spending := []entity.StatusSpending{}
+	err := r.db.Model(&entity.Ticket{}).
+		Select("status, COUNT(*) AS count, COALESCE(SUM(total_price), 0) AS amount").
+		Where("user_id = ?", userID).
+		Group("status").
+		Order("count DESC, status ASC").
+		Scan(&spending).Error
+	return spending, err
+}
+
+// GetUserSpendingByEvent sums a user's non-cancelled tickets per event, biggest spend
+// first. Deleted events are kept since the purchases still happened.
+func (r *ticketRepository) GetUserSpendingByEvent(userID string) ([]entity.EventSpending, error) {
+	spending := []entity.EventSpending{}
+	err := r.db.Model(&entity.Ticket{}).
+		Select("tickets.event_id, events.name AS event_name, COUNT(*) AS count, COALESCE(SUM(tickets.total_price), 0) AS amount").
+		Joins("JOIN events ON events.id = tickets.event_id").
+		Where("tickets.user_id = ? AND tickets.status != ?", userID, entity.TicketStatusCancelled).
+		Group("tickets.event_id, events.name").
+		Order("amount DESC, tickets.event_id ASC").
+		Scan(&spending).Error
+	return spending, err
+}
+
+// GetActiveTicketTotals counts the active tickets of each event with their seats and
+// total price. Events without active tickets are not returned.
+func (r *ticketRepository) GetActiveTicketTotals(eventIDs []string) ([]entity.EventTicketTotals, error) {
+	totals := []entity.EventTicketTotals{}
+	err := r.db.Model(&entity.Ticket{}).
+		Select("event_id, COUNT(*) AS tickets, COALESCE(SUM(quantity), 0) AS seats, COALESCE(SUM(total_price), 0) AS refund_total").
+		Where("event_id IN ? AND status = ?", eventIDs, entity.TicketStatusActive).
+		Group("event_id").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// salesBuckets holds, per dialect, the expression truncating purchase_date to the
+// start of its hour, day or week (weeks start on Monday)
+var salesBuckets = map[string]map[string]string{
+	"mysql": {
+		"hour": "DATE_ADD(DATE(purchase_date), INTERVAL HOUR(purchase_date) HOUR)",
+		"day":  "DATE(purchase_date)",
+		"week": "DATE_SUB(DATE(purchase_date), INTERVAL WEEKDAY(purchase_date) DAY)",
+	},
+	"postgres": {
+		"hour": "date_trunc('hour', purchase_date)",
+		"day":  "date_trunc('day', purchase_date)",
+		"week": "date_trunc('week', purchase_date)",
+	},
+}
+
+// GetEventSalesTimeline sums an event's paid, non-cancelled sales per hour, day or
+// week of purchase. Buckets without sales are not returned.
+func (r *ticketRepository) GetEventSalesTimeline(eventID, interval string) ([]entity.SalesTimelinePoint, error) {
+	bucket, ok := salesBuckets[r.db.Dialector.Name()][interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeline interval %q", interval)
+	}
+
+	db := fromReplica(r.db)
+	points := []entity.SalesTimelinePoint{}
+	err := db.Model(&entity.Ticket{}).
+		Select(bucket+" AS period, COALESCE(SUM(quantity), 0) AS tickets_sold, COALESCE(SUM(total_price), 0) AS revenue").
+		Where("event_id = ? AND status != ? AND is_comp = ?", eventID, entity.TicketStatusCancelled, false).
+		Group("period").
+		Order("period ASC").
+		Scan(&points).Error
+	return points, err
+}
+
 func (r *ticketRepository) GetRevenueByDateRange(startDate, endDate time.Time) (float64, error) {
+	db := fromReplica(r.db)
 	var revenue float64
-	err := r.db.Model(&entity.Ticket{}).
+	err := db.Model(&entity.Ticket{}).
 		Where("purchase_date BETWEEN ? AND ? AND status != ?", startDate, endDate, entity.TicketStatusCancelled).
 		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&revenue)
 	return revenue, err
 }
 
 func (r *ticketRepository) GetTicketsSoldByDateRange(startDate, endDate time.Time) (int, error) {
+	db := fromReplica(r.db)
 	var count int64
-	err := r.db.Model(&entity.Ticket{}).
+	err := db.Model(&entity.Ticket{}).
 		Where("purchase_date BETWEEN ? AND ? AND status != ?", startDate, endDate, entity.TicketStatusCancelled).
 		Count(&count).Error
 	return int(count), err
-} 
\ No newline at end of file
+}
+
+func (r *ticketRepository) GetReportByCategory(filter *entity.DateRangeFilter) ([]entity.CategoryReport, error) {
+	db := fromReplica(r.db)
+	reports := []entity.CategoryReport{}
+
+	query := db.Model(&entity.Ticket{}).
+		Select("events.category AS category, COALESCE(SUM(tickets.quantity), 0) AS tickets_sold, COALESCE(SUM(tickets.total_price), 0) AS revenue").
+		Joins("JOIN events ON tickets.event_id = events.id").
+		Where("tickets.status != ?", entity.TicketStatusCancelled)
+
+	// Apply date range filter
+	if filter != nil {
+		if filter.StartDate != nil {
+			query = query.Where("tickets.purchase_date >= ?", *filter.StartDate)
+		}
+		if filter.EndDate != nil {
+			query = query.Where("tickets.purchase_date <= ?", *filter.EndDate)
+		}
+	}
+
+	err := query.Group("events.category").Order("revenue DESC").Scan(&reports).Error
+	return reports, err
+}
+
+func (r *ticketRepository) GetReportByLocation(pagination *entity.Pagination, filter *entity.DateRangeFilter) ([]entity.LocationReport, int64, error) {
+	db := fromReplica(r.db)
+	reports := []entity.LocationReport{}
+	var total int64
+
+	query := db.Model(&entity.Ticket{}).
+		Select("events.location AS location, COALESCE(SUM(tickets.quantity), 0) AS tickets_sold, COALESCE(SUM(tickets.total_price), 0) AS revenue").
+		Joins("JOIN events ON tickets.event_id = events.id").
+		Where("tickets.status != ?", entity.TicketStatusCancelled)
+
+	// Apply date range filter
+	if filter != nil {
+		if filter.StartDate != nil {
+			query = query.Where("tickets.purchase_date >= ?", *filter.StartDate)
+		}
+		if filter.EndDate != nil {
+			query = query.Where("tickets.purchase_date <= ?", *filter.EndDate)
+		}
+	}
+
+	query = query.Group("events.location")
+
+	// Count grouped rows
+	if err := db.Table("(?) AS locations", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination and ordering
+	if pagination != nil {
+		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
+	}
+
+	err := query.Order("revenue DESC").Scan(&reports).Error
+	return reports, total, err
+}
@@ -1,20 +1,32 @@
 package repository
 
 import (
+	"errors"
 	"ticketing-system/entity"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	Create(user *entity.User) error
 	GetByID(id string) (*entity.User, error)
 	GetByEmail(email string) (*entity.User, error)
+	GetByEmailChangeToken(tokenHash string) (*entity.User, error)
 	Update(user *entity.User) error
 	Delete(id string) error
-	GetAll(pagination *entity.Pagination, search *entity.Search) ([]entity.User, int64, error)
+	GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.UserFilter) ([]entity.User, int64, error)
+	CountUpcomingTickets(userID string) (int64, error)
+	CreateRefreshToken(token *entity.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error)
+	RotateRefreshToken(current *entity.RefreshToken, next *entity.RefreshToken) (bool, error)
+	RevokeRefreshTokens(userID string) error
 }
 
+// errAlreadyRotated rolls back a rotation that lost the race for the current token
+var errAlreadyRotated = errors.New("refresh token already rotated")
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -45,6 +57,15 @@ func (r *userRepository) GetByEmail(email string) (*entity.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) GetByEmailChangeToken(tokenHash string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Where("email_change_token = ?", tokenHash).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(user *entity.User) error {
 	return r.db.Save(user).Error
 }
@@ -53,11 +74,12 @@ func (r *userRepository) Delete(id string) error {
 	return r.db.Delete(&entity.User{}, "id = ?", id).Error
 }
 
-func (r *userRepository) GetAll(pagination *entity.Pagination, search *entity.Search) ([]entity.User, int64, error) {
+func (r *userRepository) GetAll(pagination *entity.Pagination, search *entity.Search, filter *entity.UserFilter) ([]entity.User, int64, error) {
+	db := fromReplica(r.db)
 	var users []entity.User
 	var total int64
 
-	query := r.db.Model(&entity.User{})
+	query := db.Model(&entity.User{})
 
 	// Apply search filter
 	if search != nil && search.Query != "" {
@@ -65,11 +87,32 @@ func (r *userRepository) GetAll(pagination *entity.Pagination, search *entity.Se
 		query = query.Where("name LIKE ? OR email LIKE ?", searchQuery, searchQuery)
 	}
 
+	// Purchases are checked with a subquery rather than a join, so each user is counted once
+	if filter != nil && filter.HasTickets != nil {
+		purchases := r.db.Model(&entity.Ticket{}).Select("1").Where("tickets.user_id = users.id AND tickets.is_comp = ?", false)
+		if *filter.HasTickets {
+			query = query.Where("EXISTS (?)", purchases)
+		} else {
+			query = query.Where("NOT EXISTS (?)", purchases)
+		}
+	}
+
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	// Rank exact email matches first, then name prefix matches, then substring matches
+	if search != nil && search.Query != "" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN email = ? THEN 0 WHEN name LIKE ? THEN 1 ELSE 2 END, created_at DESC, id DESC",
+			Vars:               []interface{}{search.Query, search.Query + "%"},
+			WithoutParentheses: true,
+		}})
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+
 	// Apply pagination
 	if pagination != nil {
 		query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
@@ -77,4 +120,59 @@ func (r *userRepository) GetAll(pagination *entity.Pagination, search *entity.Se
 
 	err := query.Find(&users).Error
 	return users, total, err
-} 
\ No newline at end of file
+}
+
+// CountUpcomingTickets counts the user's active tickets for events that have not started yet
+func (r *userRepository) CountUpcomingTickets(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Ticket{}).
+		Joins("JOIN events ON events.id = tickets.event_id AND events.deleted_at IS NULL").
+		Where("tickets.user_id = ? AND tickets.status = ? AND events.event_date > ?", userID, entity.TicketStatusActive, time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) CreateRefreshToken(token *entity.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *userRepository) GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken revokes current and stores next as its successor in one transaction.
+// It reports false when current was revoked in the meantime, e.g. by a concurrent refresh.
+func (r *userRepository) RotateRefreshToken(current *entity.RefreshToken, next *entity.RefreshToken) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": next.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyRotated
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadyRotated) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RevokeRefreshTokens revokes every outstanding refresh token of the user
+func (r *userRepository) RevokeRefreshTokens(userID string) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
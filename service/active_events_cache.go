@@ -0,0 +1,80 @@
+package service
+
+import (
+	"sync"
+	"ticketing-system/entity"
+	"time"
+)
+
+// ActiveEventsCache keeps the active events listing in memory for a short TTL. Event
+// writes and ticket sales invalidate it; each instance keeps its own copy, so writes
+// made by other instances show up once the TTL expires. A nil cache is disabled.
+type ActiveEventsCache struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	events     []entity.Event
+	loaded     bool
+	expiresAt  time.Time
+	generation uint64
+}
+
+// NewActiveEventsCache returns nil, disabling the cache, when ttl is not positive
+func NewActiveEventsCache(ttl time.Duration) *ActiveEventsCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &ActiveEventsCache{ttl: ttl}
+}
+
+// Get returns the cached events, calling load to refresh them once expired
+func (c *ActiveEventsCache) Get(load func() ([]entity.Event, error)) ([]entity.Event, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	if c.loaded && time.Now().Before(c.expiresAt) {
+		events := copyEvents(c.events)
+		c.mu.Unlock()
+		return events, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	events, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// An invalidation while loading means the result may already be stale
+	if c.generation == generation {
+		c.events = copyEvents(events)
+		c.loaded = true
+		c.expiresAt = time.Now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+
+	return events, nil
+}
+
+// Invalidate drops the cached events so the next Get reloads them
+func (c *ActiveEventsCache) Invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.events = nil
+	c.loaded = false
+	c.generation++
+	c.mu.Unlock()
+}
+
+// copyEvents keeps callers from modifying the cached slice
+func copyEvents(events []entity.Event) []entity.Event {
+	copied := make([]entity.Event, len(events))
+	copy(copied, events)
+	return copied
+}
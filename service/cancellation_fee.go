@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CancellationFeeTier keeps Percent of the ticket price when the holder cancels at
+// least MinNotice before the event
+type CancellationFeeTier struct {
+	MinNotice time.Duration
+	Percent   float64
+}
+
+// ParseCancellationFeeTiers reads comma separated "hours:percent" tiers, e.g.
+// "168:0,24:50,0:100" for free cancellation a week ahead, half the price from a day
+// ahead and no refund after that. Empty means cancellations are always free.
+func ParseCancellationFeeTiers(spec string) ([]CancellationFeeTier, error) {
+	var tiers []CancellationFeeTier
+	seen := make(map[time.Duration]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		hoursValue, percentValue, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("cancellation fee tier %q must be hours:percent", part)
+		}
+		hours, err := strconv.Atoi(strings.TrimSpace(hoursValue))
+		if err != nil || hours < 0 {
+			return nil, fmt.Errorf("cancellation fee tier %q has invalid hours", part)
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSpace(percentValue), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("cancellation fee tier %q must have a percent between 0 and 100", part)
+		}
+
+		notice := time.Duration(hours) * time.Hour
+		if seen[notice] {
+			return nil, fmt.Errorf("duplicate cancellation fee tier for %d hours", hours)
+		}
+		seen[notice] = true
+		tiers = append(tiers, CancellationFeeTier{MinNotice: notice, Percent: percent})
+	}
+
+	// Longest notice first, so the first tier met is the one that applies
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinNotice > tiers[j].MinNotice })
+	return tiers, nil
+}
+
+// CancellationFeePercent returns the percentage kept for cancelling notice before the
+// event, taken from the tier with the longest notice that is met, or 0 when none is
+func (r TicketRules) CancellationFeePercent(notice time.Duration) float64 {
+	for _, tier := range r.CancellationFeeTiers {
+		if notice >= tier.MinNotice {
+			return tier.Percent
+		}
+	}
+	return 0
+}
+
+// CancellationFee is the amount of totalPrice kept for cancelling notice before the event
+func (r TicketRules) CancellationFee(totalPrice float64, notice time.Duration) float64 {
+	return math.Round(totalPrice*r.CancellationFeePercent(notice)) / 100
+}
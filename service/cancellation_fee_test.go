@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCancellationFeeTiers(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []CancellationFeeTier
+		wantErr bool
+	}{
+		{spec: "", want: nil},
+		{spec: " , ", want: nil},
+		{
+			spec: "0:100, 168:0 ,24:50",
+			want: []CancellationFeeTier{
+				{MinNotice: 168 * time.Hour, Percent: 0},
+				{MinNotice: 24 * time.Hour, Percent: 50},
+				{MinNotice: 0, Percent: 100},
+			},
+		},
+		{spec: "48:12.5", want: []CancellationFeeTier{{MinNotice: 48 * time.Hour, Percent: 12.5}}},
+		{spec: "24", wantErr: true},
+		{spec: "x:50", wantErr: true},
+		{spec: "-1:50", wantErr: true},
+		{spec: "24:abc", wantErr: true},
+		{spec: "24:-1", wantErr: true},
+		{spec: "24:100.1", wantErr: true},
+		{spec: "24:50,24:60", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCancellationFeeTiers(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCancellationFeeTiers(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseCancellationFeeTiers(%q) = %v, want %v", tt.spec, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseCancellationFeeTiers(%q)[%d] = %v, want %v", tt.spec, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestCancellationFeePercentAtTierBoundaries(t *testing.T) {
+	tiers, err := ParseCancellationFeeTiers("168:0,24:50,0:100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := TicketRules{CancellationFeeTiers: tiers}
+
+	tests := []struct {
+		notice time.Duration
+		want   float64
+	}{
+		{notice: 200 * time.Hour, want: 0},
+		{notice: 168 * time.Hour, want: 0},
+		{notice: 168*time.Hour - time.Nanosecond, want: 50},
+		{notice: 24 * time.Hour, want: 50},
+		{notice: 24*time.Hour - time.Nanosecond, want: 100},
+		{notice: 0, want: 100},
+		{notice: -time.Hour, want: 0}, // after the start no tier is met
+	}
+
+	for _, tt := range tests {
+		if got := rules.CancellationFeePercent(tt.notice); got != tt.want {
+			t.Errorf("CancellationFeePercent(%v) = %v, want %v", tt.notice, got, tt.want)
+		}
+	}
+}
+
+func TestCancellationFee(t *testing.T) {
+	tiers, err := ParseCancellationFeeTiers("24:33.333,0:100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := TicketRules{CancellationFeeTiers: tiers}
+
+	tests := []struct {
+		total  float64
+		notice time.Duration
+		want   float64
+	}{
+		{total: 100, notice: 48 * time.Hour, want: 33.33},
+		{total: 10.01, notice: 24 * time.Hour, want: 3.34},
+		{total: 59.99, notice: time.Hour, want: 59.99},
+		{total: 0, notice: time.Hour, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := rules.CancellationFee(tt.total, tt.notice); got != tt.want {
+			t.Errorf("CancellationFee(%v, %v) = %v, want %v", tt.total, tt.notice, got, tt.want)
+		}
+	}
+
+	if got := (TicketRules{}).CancellationFee(100, time.Hour); got != 0 {
+		t.Errorf("CancellationFee without tiers = %v, want 0", got)
+	}
+}
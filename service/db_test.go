@@ -0,0 +1,164 @@
+package service
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"ticketing-system/config"
+	"ticketing-system/entity"
+	"ticketing-system/repository"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the database in TEST_DATABASE_DSN, migrates it and empties every
+// table. TEST_DATABASE_DRIVER selects postgres (the default) or mysql. Tests that need a
+// database are skipped when the DSN is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	var dialector gorm.Dialector
+	switch driver := os.Getenv("TEST_DATABASE_DRIVER"); driver {
+	case "", "postgres":
+		dialector = postgres.Open(dsn)
+	case "mysql":
+		dialector = mysql.Open(dsn)
+	default:
+		t.Fatalf("unsupported TEST_DATABASE_DRIVER %q, use mysql or postgres", driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+
+	models := []interface{}{&entity.RefreshToken{}, &entity.EventStatusChange{}, &entity.Ticket{}, &entity.Event{}, &entity.User{}}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+	if err := config.CreateLiveEventNameIndex(db); err != nil {
+		t.Fatalf("create live event name index: %v", err)
+	}
+	for _, model := range models {
+		if err := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(model).Error; err != nil {
+			t.Fatalf("empty test database: %v", err)
+		}
+	}
+
+	return db
+}
+
+// recordingNotifier keeps every message instead of sending it
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []string // recipients, in send order
+}
+
+func (n *recordingNotifier) Send(to, subject, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, to)
+	return nil
+}
+
+func (n *recordingNotifier) recipients() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.sent...)
+}
+
+func newTestTicketService(t *testing.T, db *gorm.DB, notifier Notifier, rules TicketRules) TicketService {
+	t.Helper()
+
+	emails, err := LoadEmailTemplates("")
+	if err != nil {
+		t.Fatalf("load email templates: %v", err)
+	}
+
+	return NewTicketService(
+		repository.NewTicketRepository(db),
+		repository.NewEventRepository(db),
+		repository.NewUserRepository(db),
+		db,
+		notifier,
+		emails,
+		rules,
+		0,
+		nil,
+	)
+}
+
+func newTestEventService(t *testing.T, db *gorm.DB, notifier Notifier) EventService {
+	t.Helper()
+
+	emails, err := LoadEmailTemplates("")
+	if err != nil {
+		t.Fatalf("load email templates: %v", err)
+	}
+
+	return NewEventService(repository.NewEventRepository(db), db, entity.EventStatusActive, EventNameRules{}, 0, 0, notifier, emails, nil)
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) *entity.User {
+	t.Helper()
+
+	user := &entity.User{Email: email, Password: "x", Name: "Test User", Role: entity.RoleUser, IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func createTestEvent(t *testing.T, db *gorm.DB, capacity int, eventDate time.Time) *entity.Event {
+	t.Helper()
+
+	event := &entity.Event{
+		Name:       "Test Event",
+		Category:   "music",
+		Capacity:   capacity,
+		Available:  capacity,
+		Price:      10,
+		Location:   "Hall",
+		EventDate:  eventDate,
+		Status:     entity.EventStatusActive,
+		Visibility: entity.EventVisibilityPublic,
+	}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	return event
+}
+
+// assertSeatsBalance checks that the event's available seats plus the seats held by
+// tickets that are not cancelled add up to its capacity
+func assertSeatsBalance(t *testing.T, db *gorm.DB, eventID string) {
+	t.Helper()
+
+	var event entity.Event
+	if err := db.Where("id = ?", eventID).First(&event).Error; err != nil {
+		t.Fatalf("load event: %v", err)
+	}
+
+	var held int64
+	if err := db.Model(&entity.Ticket{}).
+		Where("event_id = ? AND status != ?", eventID, entity.TicketStatusCancelled).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&held).Error; err != nil {
+		t.Fatalf("sum held seats: %v", err)
+	}
+
+	if event.Available < 0 {
+		t.Fatalf("available = %d, must not be negative", event.Available)
+	}
+	if int64(event.Available)+held != int64(event.Capacity) {
+		t.Fatalf("available %d + held %d != capacity %d", event.Available, held, event.Capacity)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"ticketing-system/entity"
+	"time"
+)
+
+// Email template names; each has a <name>.subject.tmpl and <name>.body.tmpl file
+const (
+	EmailTemplatePurchase     = "purchase"
+	EmailTemplateReminder     = "reminder"
+	EmailTemplateEmailChange  = "email_change"
+	EmailTemplateEmailChanged = "email_changed"
+	EmailTemplateEventCancel  = "event_cancelled"
+)
+
+var emailTemplateNames = []string{
+	EmailTemplatePurchase,
+	EmailTemplateReminder,
+	EmailTemplateEmailChange,
+	EmailTemplateEmailChanged,
+	EmailTemplateEventCancel,
+}
+
+//go:embed email_templates/*.tmpl
+var defaultEmailTemplates embed.FS
+
+// EmailData is passed to every email template; fields unrelated to a message are left empty
+type EmailData struct {
+	User      *entity.User
+	Ticket    *entity.Ticket
+	Event     *entity.Event
+	Link      string
+	ExpiresAt time.Time
+}
+
+var emailTemplateFuncs = map[string]any{
+	"date": func(t time.Time) string { return t.UTC().Format(time.RFC1123) },
+}
+
+type emailTemplate struct {
+	subject *texttemplate.Template
+	body    *htmltemplate.Template
+}
+
+// EmailTemplates renders notification subjects and HTML bodies. Subjects are plain
+// text templates, bodies are html/template so ticket and event data is escaped.
+type EmailTemplates struct {
+	templates map[string]emailTemplate
+}
+
+// LoadEmailTemplates parses the embedded default templates, replacing any that have a
+// file of the same name in overrideDir (empty uses the defaults only). Every template
+// is rendered once with sample data so mistakes surface at startup, not on first send.
+func LoadEmailTemplates(overrideDir string) (*EmailTemplates, error) {
+	t := &EmailTemplates{templates: make(map[string]emailTemplate, len(emailTemplateNames))}
+
+	for _, name := range emailTemplateNames {
+		subjectSrc, err := readEmailTemplate(overrideDir, name+".subject.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		bodySrc, err := readEmailTemplate(overrideDir, name+".body.tmpl")
+		if err != nil {
+			return nil, err
+		}
+
+		subject, err := texttemplate.New(name + ".subject").Funcs(emailTemplateFuncs).Option("missingkey=error").Parse(subjectSrc)
+		if err != nil {
+			return nil, fmt.Errorf("email template %s subject: %w", name, err)
+		}
+		body, err := htmltemplate.New(name + ".body").Funcs(emailTemplateFuncs).Option("missingkey=error").Parse(bodySrc)
+		if err != nil {
+			return nil, fmt.Errorf("email template %s body: %w", name, err)
+		}
+		t.templates[name] = emailTemplate{subject: subject, body: body}
+
+		if _, _, err := t.Render(name, sampleEmailData()); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func readEmailTemplate(overrideDir, file string) (string, error) {
+	if overrideDir != "" {
+		content, err := os.ReadFile(filepath.Join(overrideDir, file))
+		if err == nil {
+			return string(content), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("email template %s: %w", file, err)
+		}
+	}
+
+	content, err := defaultEmailTemplates.ReadFile("email_templates/" + file)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// sampleEmailData fills every field so templates referencing missing fields fail validation
+func sampleEmailData() EmailData {
+	return EmailData{
+		User:      &entity.User{},
+		Ticket:    &entity.Ticket{},
+		Event:     &entity.Event{},
+		Link:      "https://example.com",
+		ExpiresAt: time.Now(),
+	}
+}
+
+// Render returns the subject and body of the named template. The subject is collapsed
+// onto one line so template data cannot inject extra headers.
+func (t *EmailTemplates) Render(name string, data EmailData) (string, string, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %s", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("email template %s subject: %w", name, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("email template %s body: %w", name, err)
+	}
+
+	return strings.Join(strings.Fields(subject.String()), " "), strings.TrimSpace(body.String()), nil
+}
@@ -1,42 +1,178 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"log"
+	"net/mail"
+	"strings"
 	"ticketing-system/entity"
 	"ticketing-system/repository"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventService interface {
-	CreateEvent(req *entity.CreateEventRequest) (*entity.Event, error)
+	CreateEvent(req *entity.CreateEventRequest, actorID string) (*entity.Event, error)
 	GetEventByID(id string) (*entity.Event, error)
+	GetEventsByIDs(ids []string, includeHidden bool) ([]entity.Event, error)
 	UpdateEvent(id string, req *entity.UpdateEventRequest) (*entity.Event, error)
 	DeleteEvent(id string) error
 	GetAllEvents(pagination *entity.Pagination, search *entity.Search, filter *entity.EventFilter) ([]entity.Event, *entity.PaginationMeta, error)
 	GetActiveEvents() ([]entity.Event, error)
+	GetDeletedEvents(pagination *entity.Pagination) ([]entity.Event, *entity.PaginationMeta, error)
 	GetUpcomingEvents(limit int) ([]entity.Event, error)
+	GetTrendingEvents(limit int) ([]entity.TrendingEvent, error)
+	CancelEvent(id, actorID string) (*entity.EventCancellationSummary, error)
+	PublishEvent(id, actorID string) (*entity.Event, error)
+	SetSalesPaused(id string, req *entity.EventSalesRequest) (*entity.Event, error)
+	GetEventByShareToken(token string) (*entity.Event, error)
+	RegenerateShareToken(id string) (*entity.Event, error)
+	BulkUpdateStatus(req *entity.BulkEventStatusRequest, actorID string) ([]entity.BulkEventStatusResult, error)
+	GetStatusHistory(id string) ([]entity.EventStatusChange, error)
 }
 
+// EventNameRules limits what an event name may contain
+type EventNameRules struct {
+	MaxLength    int      // in characters, capped at the column size
+	BlockedWords []string // matched case-insensitively against whole words
+}
+
+// maxEventNameColumn is the size of the events.name column
+const maxEventNameColumn = 191
+
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo     repository.EventRepository
+	db            *gorm.DB
+	defaultStatus entity.EventStatus
+	nameRules     EventNameRules
+
+	trendingWindow time.Duration
+	maxAdvance     time.Duration // 0 allows any future event date
+	notifier       Notifier
+	emails         *EmailTemplates
+	activeCache    *ActiveEventsCache
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
+func NewEventService(eventRepo repository.EventRepository, db *gorm.DB, defaultStatus entity.EventStatus, nameRules EventNameRules, trendingWindow, maxAdvance time.Duration, notifier Notifier, emails *EmailTemplates, activeCache *ActiveEventsCache) EventService {
+	// Only draft and active make sense as a starting status
+	if defaultStatus != entity.EventStatusDraft {
+		defaultStatus = entity.EventStatusActive
+	}
+
+	if nameRules.MaxLength <= 0 || nameRules.MaxLength > maxEventNameColumn {
+		nameRules.MaxLength = maxEventNameColumn
+	}
+	for i, word := range nameRules.BlockedWords {
+		nameRules.BlockedWords[i] = strings.ToLower(strings.TrimSpace(word))
+	}
+
+	if trendingWindow <= 0 {
+		trendingWindow = 24 * time.Hour
+	}
+
 	return &eventService{
-		eventRepo: eventRepo,
+		eventRepo:      eventRepo,
+		db:             db,
+		defaultStatus:  defaultStatus,
+		nameRules:      nameRules,
+		trendingWindow: trendingWindow,
+		maxAdvance:     maxAdvance,
+		notifier:       notifier,
+		emails:         emails,
+		activeCache:    activeCache,
 	}
 }
 
-func (s *eventService) CreateEvent(req *entity.CreateEventRequest) (*entity.Event, error) {
+// normalizeName trims an event name and checks it against the name rules
+func (s *eventService) normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	length := utf8.RuneCountInString(name)
+	if length < 3 {
+		return "", errors.New("name must be at least 3 characters")
+	}
+	if length > s.nameRules.MaxLength {
+		return "", errors.New("name is too long")
+	}
+
+	hasAlphanumeric := false
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "", errors.New("name contains invalid characters")
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			hasAlphanumeric = true
+		}
+	}
+	if !hasAlphanumeric {
+		return "", errors.New("name must contain a letter or digit")
+	}
+
+	if len(s.nameRules.BlockedWords) > 0 {
+		words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			for _, blocked := range s.nameRules.BlockedWords {
+				if blocked != "" && word == blocked {
+					return "", errors.New("name contains a blocked word")
+				}
+			}
+		}
+	}
+
+	return name, nil
+}
+
+// tooFarAhead catches mistyped years such as 2099 beyond the configured advance window
+func (s *eventService) tooFarAhead(date time.Time) bool {
+	return s.maxAdvance > 0 && date.After(time.Now().Add(s.maxAdvance))
+}
+
+func (s *eventService) CreateEvent(req *entity.CreateEventRequest, actorID string) (*entity.Event, error) {
 	// Validate event date
 	if req.EventDate.Before(time.Now()) {
 		return nil, errors.New("event date cannot be in the past")
 	}
+	if s.tooFarAhead(req.EventDate) {
+		return nil, errors.New("event date is too far in the future")
+	}
+
+	// Binding does not run validate tags, so enforce inventory rules here
+	if req.Capacity < 1 {
+		return nil, errors.New("capacity must be at least 1")
+	}
+
+	if req.MinAge < 0 {
+		return nil, errors.New("minimum age cannot be negative")
+	}
+
+	if req.TaxRate < 0 {
+		return nil, errors.New("tax rate cannot be negative")
+	}
 
-	// Check if event name already exists
-	existingEvent, err := s.eventRepo.GetByName(req.Name)
+	// Validate sale close time
+	if req.SaleEndsAt != nil && req.SaleEndsAt.After(req.EventDate) {
+		return nil, errors.New("sale end time cannot be after event date")
+	}
+
+	if req.CheckInGrace != nil && *req.CheckInGrace < 0 {
+		return nil, errors.New("check-in grace cannot be negative")
+	}
+
+	name, err := s.normalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if event name already exists, ignoring case
+	existingEvent, err := s.eventRepo.GetByName(name)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
@@ -44,22 +180,68 @@ func (s *eventService) CreateEvent(req *entity.CreateEventRequest) (*entity.Even
 		return nil, errors.New("event name already exists")
 	}
 
+	// Resolve initial status, letting the request override the configured default
+	status := s.defaultStatus
+	if req.Draft != nil {
+		status = entity.EventStatusActive
+		if *req.Draft {
+			status = entity.EventStatusDraft
+		}
+	}
+
+	visibility := entity.EventVisibilityPublic
+	if req.Visibility != "" {
+		if req.Visibility != entity.EventVisibilityPublic && req.Visibility != entity.EventVisibilityPrivate {
+			return nil, errors.New("visibility must be public or private")
+		}
+		visibility = req.Visibility
+	}
+
 	// Create event
 	event := &entity.Event{
-		Name:        req.Name,
+		Name:        name,
 		Description: req.Description,
 		Category:    req.Category,
 		Capacity:    req.Capacity,
 		Available:   req.Capacity,
 		Price:       req.Price,
+		TaxRate:     req.TaxRate,
 		Location:    req.Location,
 		EventDate:   req.EventDate,
-		Status:      entity.EventStatusActive,
+		SaleEndsAt:  req.SaleEndsAt,
+		Status:      status,
+		Visibility:  visibility,
+
+		MinAge:         req.MinAge,
+		AgeRestriction: req.AgeRestriction,
+		CheckInGrace:   req.CheckInGrace,
+
+		CreatedBy: actorID,
 	}
 
-	if err := s.eventRepo.Create(event); err != nil {
+	// Private events are reachable only through their share token
+	if event.IsPrivate() {
+		token, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		event.ShareToken = token
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.eventRepo.CreateWithTx(tx, event); err != nil {
+			return err
+		}
+		return s.recordStatusChange(tx, event.ID, "", event.Status, actorID)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Another request created the same name after our check
+		return nil, errors.New("event name already exists")
+	}
+	if err != nil {
 		return nil, err
 	}
+	s.activeCache.Invalidate()
 
 	return event, nil
 }
@@ -68,76 +250,202 @@ func (s *eventService) GetEventByID(id string) (*entity.Event, error) {
 	return s.eventRepo.GetByID(id)
 }
 
-func (s *eventService) UpdateEvent(id string, req *entity.UpdateEventRequest) (*entity.Event, error) {
-	event, err := s.eventRepo.GetByID(id)
+// GetEventsByIDs returns the requested events in request order. Unknown ids are
+// skipped, as are drafts and private events unless includeHidden is set.
+func (s *eventService) GetEventsByIDs(ids []string, includeHidden bool) ([]entity.Event, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids are required")
+	}
+	if len(ids) > entity.MaxPageLimit {
+		return nil, errors.New("too many ids in one request")
+	}
+
+	found, err := s.eventRepo.GetByIDs(ids)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if event can be modified
-	if !event.CanBeModified() {
-		return nil, errors.New("cannot modify event that is not active")
+	byID := make(map[string]entity.Event, len(found))
+	for _, event := range found {
+		byID[event.ID] = event
+	}
+
+	events := make([]entity.Event, 0, len(found))
+	for _, id := range ids {
+		event, ok := byID[id]
+		if !ok {
+			continue
+		}
+		// Dropping the entry also skips duplicate ids
+		delete(byID, id)
+
+		if !includeHidden && (event.IsDraft() || event.IsPrivate()) {
+			continue
+		}
+		events = append(events, event)
 	}
 
-	// Update fields if provided
-	if req.Name != nil {
-		// Check if new name is already taken
-		if *req.Name != event.Name {
-			existingEvent, err := s.eventRepo.GetByName(*req.Name)
+	return events, nil
+}
+
+func (s *eventService) UpdateEvent(id string, req *entity.UpdateEventRequest) (*entity.Event, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the event so capacity is recomputed from the same row purchases and
+		// cancellations are changing
+		var event entity.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&event).Error; err != nil {
+			return err
+		}
+
+		// Check if event can be modified
+		if !event.CanBeModified() {
+			return errors.New("cannot modify event that is not active")
+		}
+
+		// Only the changed columns are written, so concurrent edits to other fields survive
+		updates := map[string]interface{}{}
+
+		// Update fields if provided
+		if req.Name != nil {
+			name, err := s.normalizeName(*req.Name)
+			if err != nil {
+				return err
+			}
+
+			// Check if new name is already taken, ignoring case
+			existingEvent, err := s.eventRepo.GetByName(name)
 			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, err
+				return err
 			}
-			if existingEvent != nil {
-				return nil, errors.New("event name already exists")
+			if existingEvent != nil && existingEvent.ID != event.ID {
+				return errors.New("event name already exists")
 			}
+			event.Name = name
+			updates["name"] = name
 		}
-		event.Name = *req.Name
-	}
 
-	if req.Description != nil {
-		event.Description = *req.Description
-	}
+		if req.Description != nil {
+			event.Description = *req.Description
+			updates["description"] = event.Description
+		}
 
-	if req.Category != nil {
-		event.Category = *req.Category
-	}
+		if req.Category != nil {
+			event.Category = *req.Category
+			updates["category"] = event.Category
+		}
 
-	if req.Capacity != nil {
-		if *req.Capacity < 0 {
-			return nil, errors.New("capacity cannot be negative")
+		if req.Capacity != nil {
+			if *req.Capacity < 1 {
+				return errors.New("capacity must be at least 1")
+			}
+			// Calculate new available tickets
+			soldTickets := event.Capacity - event.Available
+			if *req.Capacity < soldTickets {
+				return errors.New("cannot reduce capacity below sold tickets")
+			}
+			event.Available = *req.Capacity - soldTickets
+			event.Capacity = *req.Capacity
+			updates["capacity"] = event.Capacity
+			updates["available"] = event.Available
 		}
-		// Calculate new available tickets
-		soldTickets := event.Capacity - event.Available
-		if *req.Capacity < soldTickets {
-			return nil, errors.New("cannot reduce capacity below sold tickets")
+
+		if event.Available < 0 || event.Available > event.Capacity {
+			return errors.New("available tickets out of range")
 		}
-		event.Available = *req.Capacity - soldTickets
-		event.Capacity = *req.Capacity
-	}
 
-	if req.Price != nil {
-		if *req.Price < 0 {
-			return nil, errors.New("price cannot be negative")
+		if req.Price != nil {
+			if *req.Price < 0 {
+				return errors.New("price cannot be negative")
+			}
+			event.Price = *req.Price
+			updates["price"] = event.Price
 		}
-		event.Price = *req.Price
-	}
 
-	if req.Location != nil {
-		event.Location = *req.Location
-	}
+		if req.Location != nil {
+			event.Location = *req.Location
+			updates["location"] = event.Location
+		}
 
-	if req.EventDate != nil {
-		if req.EventDate.Before(time.Now()) {
-			return nil, errors.New("event date cannot be in the past")
+		if req.EventDate != nil {
+			if req.EventDate.Before(time.Now()) {
+				return errors.New("event date cannot be in the past")
+			}
+			if s.tooFarAhead(*req.EventDate) {
+				return errors.New("event date is too far in the future")
+			}
+			event.EventDate = *req.EventDate
+			updates["event_date"] = event.EventDate
+		}
+
+		if req.SaleEndsAt != nil {
+			event.SaleEndsAt = req.SaleEndsAt
+			updates["sale_ends_at"] = event.SaleEndsAt
+		}
+
+		if req.CheckInGrace != nil {
+			if *req.CheckInGrace < 0 {
+				return errors.New("check-in grace cannot be negative")
+			}
+			event.CheckInGrace = req.CheckInGrace
+			updates["check_in_grace_minutes"] = event.CheckInGrace
+		}
+
+		if req.TaxRate != nil {
+			if *req.TaxRate < 0 {
+				return errors.New("tax rate cannot be negative")
+			}
+			event.TaxRate = *req.TaxRate
+			updates["tax_rate"] = event.TaxRate
+		}
+
+		if req.MinAge != nil {
+			if *req.MinAge < 0 {
+				return errors.New("minimum age cannot be negative")
+			}
+			event.MinAge = *req.MinAge
+			updates["min_age"] = event.MinAge
 		}
-		event.EventDate = *req.EventDate
-	}
 
-	if err := s.eventRepo.Update(event); err != nil {
+		if req.AgeRestriction != nil {
+			event.AgeRestriction = *req.AgeRestriction
+			updates["age_restriction"] = event.AgeRestriction
+		}
+
+		if req.Visibility != nil {
+			if *req.Visibility != entity.EventVisibilityPublic && *req.Visibility != entity.EventVisibilityPrivate {
+				return errors.New("visibility must be public or private")
+			}
+			event.Visibility = *req.Visibility
+			updates["visibility"] = event.Visibility
+			if event.IsPrivate() && event.ShareToken == "" {
+				token, err := generateToken()
+				if err != nil {
+					return err
+				}
+				event.ShareToken = token
+				updates["share_token"] = token
+			}
+		}
+
+		// Sales must close no later than the event itself
+		if event.SaleEndsAt != nil && event.SaleEndsAt.After(event.EventDate) {
+			return errors.New("sale end time cannot be after event date")
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&event).Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, errors.New("event name already exists")
+	}
+	if err != nil {
 		return nil, err
 	}
+	s.activeCache.Invalidate()
 
-	return event, nil
+	return s.eventRepo.GetByID(id)
 }
 
 func (s *eventService) DeleteEvent(id string) error {
@@ -152,10 +460,37 @@ func (s *eventService) DeleteEvent(id string) error {
 		return errors.New("cannot delete event with sold tickets")
 	}
 
-	return s.eventRepo.Delete(id)
+	if err := s.eventRepo.Delete(id); err != nil {
+		return err
+	}
+	s.activeCache.Invalidate()
+
+	return nil
 }
 
 func (s *eventService) GetAllEvents(pagination *entity.Pagination, search *entity.Search, filter *entity.EventFilter) ([]entity.Event, *entity.PaginationMeta, error) {
+	for _, status := range filter.StatusList() {
+		if !entity.EventStatus(status).IsValid() {
+			return nil, nil, errors.New("invalid status filter")
+		}
+	}
+
+	if _, ok := filter.FieldColumns(); !ok {
+		return nil, nil, errors.New("invalid fields parameter")
+	}
+
+	if filter.MinAvailable != nil && *filter.MinAvailable < 0 {
+		return nil, nil, errors.New("min_available must not be negative")
+	}
+
+	if filter.CreatedByEmail != "" {
+		filter.CreatedByEmail = strings.TrimSpace(filter.CreatedByEmail)
+		address, err := mail.ParseAddress(filter.CreatedByEmail)
+		if err != nil || address.Address != filter.CreatedByEmail {
+			return nil, nil, errors.New("invalid created_by_email")
+		}
+	}
+
 	events, total, err := s.eventRepo.GetAll(pagination, search, filter)
 	if err != nil {
 		return nil, nil, err
@@ -171,8 +506,24 @@ func (s *eventService) GetAllEvents(pagination *entity.Pagination, search *entit
 	return events, meta, nil
 }
 
+func (s *eventService) GetDeletedEvents(pagination *entity.Pagination) ([]entity.Event, *entity.PaginationMeta, error) {
+	events, total, err := s.eventRepo.GetDeleted(pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &entity.PaginationMeta{
+		CurrentPage: pagination.Page,
+		TotalItems:  total,
+		Limit:       pagination.GetLimit(),
+		TotalPages:  int((total + int64(pagination.GetLimit()) - 1) / int64(pagination.GetLimit())),
+	}
+
+	return events, meta, nil
+}
+
 func (s *eventService) GetActiveEvents() ([]entity.Event, error) {
-	return s.eventRepo.GetActiveEvents()
+	return s.activeCache.Get(s.eventRepo.GetActiveEvents)
 }
 
 func (s *eventService) GetUpcomingEvents(limit int) ([]entity.Event, error) {
@@ -180,4 +531,299 @@ func (s *eventService) GetUpcomingEvents(limit int) ([]entity.Event, error) {
 		limit = 10
 	}
 	return s.eventRepo.GetUpcomingEvents(limit)
-} 
\ No newline at end of file
+}
+
+func (s *eventService) GetTrendingEvents(limit int) ([]entity.TrendingEvent, error) {
+	if limit <= 0 || limit > entity.MaxPageLimit {
+		limit = 10
+	}
+	return s.eventRepo.GetTrendingEvents(time.Now().Add(-s.trendingWindow), limit)
+}
+
+func (s *eventService) PublishEvent(id, actorID string) (*entity.Event, error) {
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.Status.CanTransitionTo(entity.EventStatusActive) {
+		return nil, errors.New("only draft events can be published")
+	}
+
+	if event.EventDate.Before(time.Now()) {
+		return nil, errors.New("event date cannot be in the past")
+	}
+
+	previousStatus := event.Status
+	event.Status = entity.EventStatusActive
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.eventRepo.UpdateWithTx(tx, event); err != nil {
+			return err
+		}
+		return s.recordStatusChange(tx, event.ID, previousStatus, event.Status, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	return event, nil
+}
+
+// SetSalesPaused pauses or resumes ticket sales of an event without touching its status
+func (s *eventService) SetSalesPaused(id string, req *entity.EventSalesRequest) (*entity.Event, error) {
+	// Binding does not run validate tags, and a missing flag must not silently resume sales
+	if req.Paused == nil {
+		return nil, errors.New("paused is required")
+	}
+
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status == entity.EventStatusCancelled || event.Status == entity.EventStatusCompleted {
+		return nil, errors.New("cannot change sales of a cancelled or completed event")
+	}
+
+	if err := s.eventRepo.SetSalesPaused(id, *req.Paused); err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	return s.eventRepo.GetByID(id)
+}
+
+// BulkUpdateStatus moves several events forward in one transaction, reporting the outcome per event.
+// Only ongoing and completed are accepted; cancellation goes through CancelEvent so tickets are released.
+func (s *eventService) BulkUpdateStatus(req *entity.BulkEventStatusRequest, actorID string) ([]entity.BulkEventStatusResult, error) {
+	if len(req.IDs) == 0 {
+		return nil, errors.New("ids are required")
+	}
+	if len(req.IDs) > entity.MaxPageLimit {
+		return nil, errors.New("too many ids in one request")
+	}
+	if req.Status != entity.EventStatusOngoing && req.Status != entity.EventStatusCompleted {
+		return nil, errors.New("status must be ongoing or completed")
+	}
+
+	results := make([]entity.BulkEventStatusResult, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range req.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			result := entity.BulkEventStatusResult{ID: id}
+
+			var event entity.Event
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&event).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+			result.PreviousStatus = event.Status
+
+			if !event.Status.CanTransitionTo(req.Status) {
+				result.Error = "cannot change status from " + string(event.Status) + " to " + string(req.Status)
+				results = append(results, result)
+				continue
+			}
+
+			if err := tx.Model(&event).Update("status", req.Status).Error; err != nil {
+				return err
+			}
+			if err := s.recordStatusChange(tx, id, result.PreviousStatus, req.Status, actorID); err != nil {
+				return err
+			}
+
+			result.Success = true
+			result.Status = req.Status
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	return results, nil
+}
+
+func (s *eventService) GetEventByShareToken(token string) (*entity.Event, error) {
+	if token == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	event, err := s.eventRepo.GetByShareToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Share links never expose unpublished events
+	if event.IsDraft() {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return event, nil
+}
+
+func (s *eventService) RegenerateShareToken(id string) (*entity.Event, error) {
+	event, err := s.eventRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.IsPrivate() {
+		return nil, errors.New("only private events have share tokens")
+	}
+
+	// Replacing the token revokes every previously shared link
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.SetShareToken(id, token); err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	return s.eventRepo.GetByID(id)
+}
+
+func (s *eventService) CancelEvent(id, actorID string) (*entity.EventCancellationSummary, error) {
+	var summary *entity.EventCancellationSummary
+	var event entity.Event
+	var tickets []entity.Ticket
+
+	// Start transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the event so concurrent purchases wait for the cancellation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&event).Error; err != nil {
+			return err
+		}
+
+		summary = &entity.EventCancellationSummary{
+			EventID:     event.ID,
+			EventName:   event.Name,
+			CancelledAt: event.UpdatedAt,
+		}
+
+		// Cancelling twice is a no-op so admins can safely retry
+		if event.Status == entity.EventStatusCancelled {
+			summary.AlreadyCancelled = true
+			return nil
+		}
+
+		if event.Status == entity.EventStatusCompleted {
+			return errors.New("cannot cancel a completed event")
+		}
+
+		if err := tx.Preload("User").Where("event_id = ? AND status = ?", id, entity.TicketStatusActive).Find(&tickets).Error; err != nil {
+			return err
+		}
+
+		for _, ticket := range tickets {
+			summary.TicketsCancelled++
+			summary.QuantityReleased += ticket.Quantity
+			summary.RefundTotal += ticket.TotalPrice
+		}
+
+		// Cancel all active tickets within transaction
+		if len(tickets) > 0 {
+			if err := tx.Model(&entity.Ticket{}).
+				Where("event_id = ? AND status = ?", id, entity.TicketStatusActive).
+				Updates(map[string]interface{}{
+					"status":        entity.TicketStatusCancelled,
+					"status_reason": "event cancelled",
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Return released tickets to availability and close the event. Only these two
+		// columns are written so concurrent edits to other fields are not overwritten.
+		previousStatus := event.Status
+		if err := tx.Model(&event).Updates(map[string]interface{}{
+			"status":    entity.EventStatusCancelled,
+			"available": gorm.Expr("LEAST(available + ?, capacity)", summary.QuantityReleased),
+		}).Error; err != nil {
+			return err
+		}
+		if err := s.recordStatusChange(tx, event.ID, previousStatus, entity.EventStatusCancelled, actorID); err != nil {
+			return err
+		}
+
+		summary.CancelledAt = event.UpdatedAt
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	if !summary.AlreadyCancelled {
+		for i := range tickets {
+			s.sendCancellationNotice(&tickets[i], &event)
+		}
+	}
+
+	return summary, nil
+}
+
+// sendCancellationNotice tells a ticket holder the event was cancelled, at the delivery
+// email when one was given. The cancellation is already committed, so a failed notice
+// is only logged.
+func (s *eventService) sendCancellationNotice(ticket *entity.Ticket, event *entity.Event) {
+	to := ticket.User.Email
+	if ticket.DeliveryEmail != "" {
+		to = ticket.DeliveryEmail
+	}
+
+	subject, body, err := s.emails.Render(EmailTemplateEventCancel, EmailData{User: &ticket.User, Ticket: ticket, Event: event})
+	if err != nil {
+		log.Printf("failed to render cancellation notice for ticket %s: %v", ticket.ID, err)
+		return
+	}
+	if err := s.notifier.Send(to, subject, body); err != nil {
+		log.Printf("failed to send cancellation notice for ticket %s: %v", ticket.ID, err)
+	}
+}
+
+func (s *eventService) GetStatusHistory(id string) ([]entity.EventStatusChange, error) {
+	// Validate event exists
+	if _, err := s.eventRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.GetStatusHistory(id)
+}
+
+// recordStatusChange appends to the event's status history within the transaction
+// that changes the status, so the history never disagrees with the event
+func (s *eventService) recordStatusChange(tx *gorm.DB, eventID string, from, to entity.EventStatus, actorID string) error {
+	return s.eventRepo.CreateStatusChangeWithTx(tx, &entity.EventStatusChange{
+		EventID:    eventID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  actorID,
+	})
+}
+
+// generateToken returns a random hex token for private event links and confirmation emails
+func generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"ticketing-system/entity"
+	"time"
+)
+
+// TestBulkUpdateStatusRacesCancellation runs a bulk status update and a cancellation of
+// the same event at once. The event row lock must make one of them see the other's
+// status, so the active status is left exactly once in the history.
+func TestBulkUpdateStatusRacesCancellation(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestEventService(t, db, &recordingNotifier{})
+	admin := createTestUser(t, db, "admin@example.com")
+
+	for round := 0; round < 10; round++ {
+		event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			svc.BulkUpdateStatus(&entity.BulkEventStatusRequest{IDs: []string{event.ID}, Status: entity.EventStatusOngoing}, admin.ID)
+		}()
+		go func() {
+			defer wg.Done()
+			svc.CancelEvent(event.ID, admin.ID)
+		}()
+		wg.Wait()
+
+		var leftActive int64
+		if err := db.Model(&entity.EventStatusChange{}).
+			Where("event_id = ? AND from_status = ?", event.ID, entity.EventStatusActive).
+			Count(&leftActive).Error; err != nil {
+			t.Fatalf("count history: %v", err)
+		}
+		if leftActive != 1 {
+			t.Fatalf("round %d: %d transitions out of active, want 1", round, leftActive)
+		}
+
+		var final entity.Event
+		db.Where("id = ?", event.ID).First(&final)
+		if final.Status != entity.EventStatusCancelled {
+			t.Fatalf("round %d: status = %s, want cancelled", round, final.Status)
+		}
+	}
+}
+
+// TestCapacityEditsKeepSeatsBalanced mixes capacity changes with purchases and
+// cancellations on events created through the service, checking the seat balance after
+// every step.
+func TestCapacityEditsKeepSeatsBalanced(t *testing.T) {
+	db := openTestDB(t)
+	events := newTestEventService(t, db, &recordingNotifier{})
+	tickets := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	admin := createTestUser(t, db, "admin@example.com")
+	var users []*entity.User
+	for i := 0; i < 3; i++ {
+		users = append(users, createTestUser(t, db, fmt.Sprintf("user%d@example.com", i)))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	var eventIDs []string
+	var bought []*entity.Ticket
+
+	for step := 0; step < 200; step++ {
+		user := users[rng.Intn(len(users))]
+
+		switch op := rng.Intn(5); {
+		case op == 0 || len(eventIDs) == 0:
+			event, err := events.CreateEvent(&entity.CreateEventRequest{
+				Name:      fmt.Sprintf("Property Event %d", step),
+				Category:  "music",
+				Capacity:  1 + rng.Intn(10),
+				Price:     10,
+				Location:  "Hall",
+				EventDate: time.Now().Add(30 * 24 * time.Hour),
+			}, admin.ID)
+			if err != nil {
+				t.Fatalf("step %d: CreateEvent: %v", step, err)
+			}
+			eventIDs = append(eventIDs, event.ID)
+		case op == 1:
+			capacity := 1 + rng.Intn(15)
+			events.UpdateEvent(eventIDs[rng.Intn(len(eventIDs))], &entity.UpdateEventRequest{Capacity: &capacity})
+		case op == 2 || len(bought) == 0:
+			eventID := eventIDs[rng.Intn(len(eventIDs))]
+			if ticket, err := tickets.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: eventID, Quantity: 1 + rng.Intn(3)}); err == nil {
+				bought = append(bought, ticket)
+			}
+		case op == 3:
+			ticket := bought[rng.Intn(len(bought))]
+			tickets.CancelTicket(ticket.ID, ticket.UserID, &entity.CancelTicketRequest{})
+		default:
+			ticket := bought[rng.Intn(len(bought))]
+			tickets.UpdateTicketStatus(ticket.ID, &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusCancelled})
+		}
+
+		for _, id := range eventIDs {
+			assertSeatsBalance(t, db, id)
+		}
+	}
+}
+
+// TestCapacityEditsRacePurchases changes capacity while purchases are running. The event
+// row lock must keep UpdateEvent from recomputing availability from a stale read.
+func TestCapacityEditsRacePurchases(t *testing.T) {
+	db := openTestDB(t)
+	events := newTestEventService(t, db, &recordingNotifier{})
+	tickets := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	event := createTestEvent(t, db, 20, time.Now().Add(7*24*time.Hour))
+	var users []*entity.User
+	for i := 0; i < 10; i++ {
+		users = append(users, createTestUser(t, db, fmt.Sprintf("racer%d@example.com", i)))
+	}
+
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(2)
+		go func(userID string) {
+			defer wg.Done()
+			tickets.BuyTicket(userID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1})
+		}(user.ID)
+		go func(capacity int) {
+			defer wg.Done()
+			events.UpdateEvent(event.ID, &entity.UpdateEventRequest{Capacity: &capacity})
+		}(15 + i)
+	}
+	wg.Wait()
+
+	assertSeatsBalance(t, db, event.ID)
+}
+
+func TestNormalizeName(t *testing.T) {
+	svc := NewEventService(nil, nil, entity.EventStatusActive, EventNameRules{
+		MaxLength:    10,
+		BlockedWords: []string{" Spam "},
+	}, 0, 0, nil, nil, nil).(*eventService)
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{name: "  Gala  ", want: "Gala"},
+		{name: "Café 2026", want: "Café 2026"},
+		{name: "ab", wantErr: "name must be at least 3 characters"},
+		{name: "  ab  ", wantErr: "name must be at least 3 characters"},
+		{name: "ééé", want: "ééé"}, // counted in characters, not bytes
+		{name: "Eleven char", wantErr: "name is too long"},
+		{name: "Bad\x00Name", wantErr: "name contains invalid characters"},
+		{name: "---", wantErr: "name must contain a letter or digit"},
+		{name: "SPAM fest", wantErr: "name contains a blocked word"},
+		{name: "Spammer", want: "Spammer"}, // whole words only
+	}
+
+	for _, tt := range tests {
+		got, err := svc.normalizeName(tt.name)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("normalizeName(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	// The name column caps the configured length
+	unlimited := NewEventService(nil, nil, entity.EventStatusActive, EventNameRules{MaxLength: 1000}, 0, 0, nil, nil, nil).(*eventService)
+	if _, err := unlimited.normalizeName(strings.Repeat("a", maxEventNameColumn+1)); err == nil {
+		t.Errorf("name longer than the column was accepted")
+	}
+}
+
+func TestCreateEventNameIsCaseInsensitive(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestEventService(t, db, &recordingNotifier{})
+	admin := createTestUser(t, db, "admin@example.com")
+
+	req := &entity.CreateEventRequest{
+		Name:      "Summer Festival",
+		Category:  "music",
+		Capacity:  10,
+		Price:     10,
+		Location:  "Park",
+		EventDate: time.Now().Add(30 * 24 * time.Hour),
+	}
+	if _, err := svc.CreateEvent(req, admin.ID); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	req.Name = "  summer FESTIVAL "
+	if _, err := svc.CreateEvent(req, admin.ID); err == nil || err.Error() != "event name already exists" {
+		t.Fatalf("CreateEvent with a differently cased name: error = %v, want event name already exists", err)
+	}
+}
+
+func TestDeletedEventNameCanBeReused(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestEventService(t, db, &recordingNotifier{})
+	admin := createTestUser(t, db, "admin@example.com")
+
+	req := &entity.CreateEventRequest{
+		Name:      "Winter Gala",
+		Category:  "music",
+		Capacity:  10,
+		Price:     10,
+		Location:  "Hall",
+		EventDate: time.Now().Add(30 * 24 * time.Hour),
+	}
+	first, err := svc.CreateEvent(req, admin.ID)
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if err := svc.DeleteEvent(first.ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+
+	second, err := svc.CreateEvent(req, admin.ID)
+	if err != nil {
+		t.Fatalf("CreateEvent reusing a deleted name: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Fatal("reused name returned the deleted event")
+	}
+
+	if _, err := svc.CreateEvent(req, admin.ID); err == nil || err.Error() != "event name already exists" {
+		t.Fatalf("CreateEvent with a live duplicate name: error = %v, want event name already exists", err)
+	}
+}
+
+// TestConcurrentCreateEventSameName races creations of one name. All of them can pass
+// the service's name check, so the database index must reject all but one.
+func TestConcurrentCreateEventSameName(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestEventService(t, db, &recordingNotifier{})
+	admin := createTestUser(t, db, "admin@example.com")
+
+	const racers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, racers)
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := svc.CreateEvent(&entity.CreateEventRequest{
+				Name:      []string{"Spring Fair", "SPRING FAIR"}[i%2],
+				Category:  "music",
+				Capacity:  10,
+				Price:     10,
+				Location:  "Park",
+				EventDate: time.Now().Add(30 * 24 * time.Hour),
+			}, admin.ID)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case err.Error() != "event name already exists":
+			t.Errorf("CreateEvent error = %v, want event name already exists", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("created %d events with the same name, want 1", created)
+	}
+}
+
+func TestTooFarAhead(t *testing.T) {
+	limited := NewEventService(nil, nil, entity.EventStatusActive, EventNameRules{}, 0, 365*24*time.Hour, nil, nil, nil).(*eventService)
+	unlimited := NewEventService(nil, nil, entity.EventStatusActive, EventNameRules{}, 0, 0, nil, nil, nil).(*eventService)
+
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"next month", time.Now().AddDate(0, 1, 0), false},
+		{"just inside the window", time.Now().Add(364 * 24 * time.Hour), false},
+		{"just outside the window", time.Now().Add(366 * 24 * time.Hour), true},
+		{"mistyped year", time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		if got := limited.tooFarAhead(tt.date); got != tt.want {
+			t.Errorf("%s: tooFarAhead = %v, want %v", tt.name, got, tt.want)
+		}
+		if unlimited.tooFarAhead(tt.date) {
+			t.Errorf("%s: tooFarAhead without a window = true", tt.name)
+		}
+	}
+
+	// CreateEvent rejects the date before touching the database
+	_, err := limited.CreateEvent(&entity.CreateEventRequest{Name: "Far Event", Capacity: 1, EventDate: time.Now().AddDate(2, 0, 0)}, "admin")
+	if err == nil || err.Error() != "event date is too far in the future" {
+		t.Errorf("CreateEvent error = %v, want event date is too far in the future", err)
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import "log"
+
+// Notifier delivers messages to users. Deployments without a mail provider use
+// the logging implementation, which writes each message to the server log.
+type Notifier interface {
+	Send(to, subject, body string) error
+}
+
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Send(to, subject, body string) error {
+	log.Printf("notification to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestComparePasswordAcrossAlgorithms(t *testing.T) {
+	// Low-cost hashers keep the test fast; comparePassword reads the cost from the hash
+	hashers := map[string]PasswordHasher{
+		HashAlgorithmBcrypt:   &bcryptHasher{cost: bcrypt.MinCost},
+		HashAlgorithmArgon2id: &argon2idHasher{time: 1, memory: 1024, threads: 1, keyLen: 32, saltLen: 16},
+	}
+
+	for name, hasher := range hashers {
+		hash, err := hasher.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("%s: Hash: %v", name, err)
+		}
+		if name == HashAlgorithmArgon2id && !strings.HasPrefix(hash, argon2idPrefix) {
+			t.Fatalf("argon2id hash %q lacks the %s prefix", hash, argon2idPrefix)
+		}
+
+		// Every hasher verifies hashes from every algorithm
+		for verifierName, verifier := range hashers {
+			if err := verifier.Compare(hash, "correct horse"); err != nil {
+				t.Errorf("%s hash, %s verifier: correct password rejected: %v", name, verifierName, err)
+			}
+			if err := verifier.Compare(hash, "wrong horse"); err == nil {
+				t.Errorf("%s hash, %s verifier: wrong password accepted", name, verifierName)
+			}
+		}
+	}
+}
+
+func TestCompareArgon2idRejectsMalformedHashes(t *testing.T) {
+	hasher := &argon2idHasher{time: 1, memory: 1024, threads: 1, keyLen: 32, saltLen: 16}
+	valid, err := hasher.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+
+	withParts := func(params, key string) string {
+		return strings.Join([]string{"", "argon2id", parts[2], params, parts[4], key}, "$")
+	}
+
+	tests := map[string]string{
+		"too few parts":  "$argon2id$v=19$m=1024,t=1,p=1$salt",
+		"wrong version":  strings.Replace(valid, "v=19", "v=16", 1),
+		"bad parameters": withParts("m=x,t=1,p=1", parts[5]),
+		"zero time":      withParts("m=1024,t=0,p=1", parts[5]),
+		"zero threads":   withParts("m=1024,t=1,p=0", parts[5]),
+		"zero memory":    withParts("m=0,t=1,p=1", parts[5]),
+		"huge memory":    withParts("m=4294967295,t=1,p=1", parts[5]),
+		"bad salt":       strings.Join([]string{"", "argon2id", parts[2], parts[3], "!!", parts[5]}, "$"),
+		"bad key":        withParts(parts[3], "!!"),
+		"empty key":      withParts(parts[3], ""),
+		"bcrypt garbage": "$2a$10$notreallyahash",
+		"empty hash":     "",
+	}
+
+	for name, hash := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: comparePassword panicked: %v", name, r)
+				}
+			}()
+			if err := comparePassword(hash, "secret"); err == nil {
+				t.Errorf("%s: malformed hash %q accepted", name, hash)
+			}
+		}()
+	}
+}
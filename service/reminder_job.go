@@ -0,0 +1,36 @@
+package service
+
+import (
+	"log"
+	"time"
+)
+
+// StartReminderJob sends event reminders in the background, once at startup and then
+// every interval, for events starting within window
+func StartReminderJob(ticketService TicketService, window, interval time.Duration) {
+	if window <= 0 || interval <= 0 {
+		log.Println("Reminder job disabled: window and interval must be positive")
+		return
+	}
+
+	run := func() {
+		sent, err := ticketService.SendEventReminders(window)
+		if err != nil {
+			log.Printf("reminder job failed after %d reminders: %v", sent, err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("reminder job sent %d reminders", sent)
+		}
+	}
+
+	go func() {
+		run()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+}
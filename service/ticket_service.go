@@ -1,23 +1,91 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"strings"
 	"ticketing-system/entity"
 	"ticketing-system/repository"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketService interface {
 	BuyTicket(userID string, req *entity.BuyTicketRequest) (*entity.Ticket, error)
+	QuoteTicket(userID string, req *entity.BuyTicketRequest) (*entity.TicketQuote, error)
+	IssueCompTicket(adminID string, req *entity.IssueCompTicketRequest) (*entity.Ticket, error)
 	GetTicketByID(id string) (*entity.Ticket, error)
-	GetUserTickets(userID string, pagination *entity.Pagination) ([]entity.Ticket, *entity.PaginationMeta, error)
-	GetAllTickets(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter) ([]entity.Ticket, *entity.PaginationMeta, error)
+	GetUserTickets(userID, eventID string, pagination *entity.Pagination, fields *entity.TicketFields) ([]entity.Ticket, *entity.PaginationMeta, error)
+	GetUserEventTickets(userID, eventID string) ([]entity.Ticket, error)
+	GetAllTickets(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter, fields *entity.TicketFields) ([]entity.Ticket, *entity.PaginationMeta, error)
+	ExportTickets(search *entity.Search, filter *entity.TicketFilter, fn func(tickets []entity.Ticket) error) error
+	ExportRefunds(filter *entity.DateRangeFilter, fn func(tickets []entity.Ticket) error) error
 	UpdateTicketStatus(ticketID string, req *entity.UpdateTicketStatusRequest) (*entity.Ticket, error)
-	CancelTicket(ticketID, userID string) (*entity.Ticket, error)
+	CancelTicket(ticketID, userID string, req *entity.CancelTicketRequest) (*entity.Ticket, error)
+	ForceCancelTicket(ticketID, adminID string, req *entity.ForceCancelTicketRequest) (*entity.Ticket, error)
+	SendEventReminders(window time.Duration) (int, error)
 	GetTicketStats() (*entity.ReportSummary, error)
 	GetEventReport(eventID string) (*entity.EventReport, error)
+	GetDetailedEventReport(eventID string) (*entity.DetailedEventReport, error)
+	GetEventSalesTimeline(eventID, interval string) (*entity.EventSalesTimeline, error)
+	GetUserSpending(userID string) (*entity.UserSpendingSummary, error)
+	GetEventsImpact(ids []string) (*entity.EventsImpactSummary, error)
+	GetReportByCategory(filter *entity.DateRangeFilter) ([]entity.CategoryReport, error)
+	GetReportByLocation(pagination *entity.Pagination, filter *entity.DateRangeFilter) ([]entity.LocationReport, *entity.PaginationMeta, error)
+}
+
+const (
+	// maxTicketQuantity caps any single purchase or comp, whatever MaxPerPurchase says
+	maxTicketQuantity = 1000
+	// maxPurchaseTotal keeps purchase totals far from float64 precision loss
+	maxPurchaseTotal = 1e12
+)
+
+// TicketRules holds the purchase and cancellation limits applied to tickets
+type TicketRules struct {
+	PurchaseCutoff     time.Duration // purchases close this long before the event
+	CancellationCutoff time.Duration // cancellations close this long before the event
+	MaxPerPurchase     int           // 0 means no limit
+	DuplicateWindow    time.Duration // repeat purchases of an event within this window are rejected, 0 disables
+	CheckInOpensBefore time.Duration // check-in opens this long before the event, 0 allows any earlier time
+	CheckInGrace       time.Duration // check-in closes this long after the event starts, 0 never closes; events may override it
+	ServiceFeePercent  float64       // percentage of the face value charged as a fee
+	ServiceFeeFlat     float64       // fixed fee charged per ticket
+
+	// Fee kept when holders cancel, by notice before the event, see ParseCancellationFeeTiers
+	CancellationFeeTiers []CancellationFeeTier
+
+	PurchaseIsolation sql.IsolationLevel // isolation for purchase transactions, LevelDefault keeps the DB default
+}
+
+// PurchaseLimit is the largest quantity one purchase may have
+func (r TicketRules) PurchaseLimit() int {
+	if r.MaxPerPurchase > 0 && r.MaxPerPurchase < maxTicketQuantity {
+		return r.MaxPerPurchase
+	}
+	return maxTicketQuantity
+}
+
+// ParseIsolationLevel maps a configured isolation name to its level; empty means the DB default
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("unsupported isolation level %q", name)
+	}
 }
 
 type ticketService struct {
@@ -25,6 +93,12 @@ type ticketService struct {
 	eventRepo  repository.EventRepository
 	userRepo   repository.UserRepository
 	db         *gorm.DB
+	notifier   Notifier
+	emails     *EmailTemplates
+	rules      TicketRules
+
+	exportBatchSize int
+	activeCache     *ActiveEventsCache // availability changes invalidate the active events listing
 }
 
 func NewTicketService(
@@ -32,12 +106,27 @@ func NewTicketService(
 	eventRepo repository.EventRepository,
 	userRepo repository.UserRepository,
 	db *gorm.DB,
+	notifier Notifier,
+	emails *EmailTemplates,
+	rules TicketRules,
+	exportBatchSize int,
+	activeCache *ActiveEventsCache,
 ) TicketService {
+	if exportBatchSize <= 0 {
+		exportBatchSize = 500
+	}
+
 	return &ticketService{
 		ticketRepo: ticketRepo,
 		eventRepo:  eventRepo,
 		userRepo:   userRepo,
 		db:         db,
+		notifier:   notifier,
+		emails:     emails,
+		rules:      rules,
+
+		exportBatchSize: exportBatchSize,
+		activeCache:     activeCache,
 	}
 }
 
@@ -45,10 +134,29 @@ func (s *ticketService) BuyTicket(userID string, req *entity.BuyTicketRequest) (
 	var ticket *entity.Ticket
 	var err error
 
+	if err := checkQuantity(req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if s.rules.MaxPerPurchase > 0 && req.Quantity > s.rules.MaxPerPurchase {
+		return nil, errors.New("quantity exceeds maximum tickets per purchase")
+	}
+
+	deliveryEmail := strings.TrimSpace(req.DeliveryEmail)
+	if deliveryEmail != "" {
+		address, err := mail.ParseAddress(deliveryEmail)
+		if err != nil || address.Address != deliveryEmail {
+			return nil, errors.New("invalid delivery email")
+		}
+	}
+
+	var user *entity.User
+
 	// Start transaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// Validate user
-		user, err := s.userRepo.GetByID(userID)
+		var err error
+		user, err = s.userRepo.GetByID(userID)
 		if err != nil {
 			return err
 		}
@@ -56,38 +164,48 @@ func (s *ticketService) BuyTicket(userID string, req *entity.BuyTicketRequest) (
 			return errors.New("user account is not active")
 		}
 
-		// Validate event with SELECT FOR UPDATE to prevent race conditions
+		// Lock the event row (SELECT ... FOR UPDATE) to prevent race conditions
 		var event entity.Event
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", req.EventID).First(&event).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", req.EventID).First(&event).Error; err != nil {
 			return err
 		}
 
-		// Check event availability
-		if !event.IsAvailable() {
-			return errors.New("event is not available for booking")
+		if err := s.checkPurchasable(&event, req.Quantity, time.Now()); err != nil {
+			return err
 		}
 
-		// Check capacity
-		if event.Available < req.Quantity {
-			return errors.New("insufficient tickets available")
+		// The event row lock above is held until commit, so concurrent purchases of the
+		// event run this check one at a time and each sees the tickets committed before it
+		if s.rules.DuplicateWindow > 0 {
+			var recent int64
+			if err := tx.Model(&entity.Ticket{}).
+				Where("user_id = ? AND event_id = ? AND is_comp = ? AND status != ? AND purchase_date > ?",
+					userID, req.EventID, false, entity.TicketStatusCancelled, time.Now().Add(-s.rules.DuplicateWindow)).
+				Count(&recent).Error; err != nil {
+				return err
+			}
+			if recent > 0 {
+				return errors.New("duplicate purchase, please wait before buying again")
+			}
 		}
 
-		// Check if event date is in the future
-		if event.EventDate.Before(time.Now().Add(time.Hour)) {
-			return errors.New("cannot purchase tickets for events starting within an hour")
+		quote, err := s.priceTickets(&event, req.Quantity)
+		if err != nil {
+			return err
 		}
 
-		// Calculate total price
-		totalPrice := event.Price * float64(req.Quantity)
-
 		// Create ticket
 		ticket = &entity.Ticket{
 			UserID:       userID,
 			EventID:      req.EventID,
 			Quantity:     req.Quantity,
-			TotalPrice:   totalPrice,
+			TotalPrice:   quote.TotalPrice,
+			TaxAmount:    quote.TaxAmount,
+			Fee:          quote.Fee,
 			Status:       entity.TicketStatusActive,
 			PurchaseDate: time.Now(),
+
+			DeliveryEmail: deliveryEmail,
 		}
 
 		// Create ticket record within transaction
@@ -96,32 +214,259 @@ func (s *ticketService) BuyTicket(userID string, req *entity.BuyTicketRequest) (
 		}
 
 		// Update event available tickets within transaction
-		if err := tx.Model(&entity.Event{}).
-			Where("id = ?", req.EventID).
-			UpdateColumn("available", gorm.Expr("available - ?", req.Quantity)).Error; err != nil {
+		return reserveSeatsWithTx(tx, req.EventID, req.Quantity)
+	}, s.purchaseTxOptions())
+
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	// Return ticket with relations
+	ticket, err = s.GetTicketByID(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendPurchaseNotice(ticket, user.Email)
+	return ticket, nil
+}
+
+// sendPurchaseNotice delivers the tickets to the delivery email, or the buyer when none
+// was given. The purchase is already committed, so a failed notice is only logged.
+func (s *ticketService) sendPurchaseNotice(ticket *entity.Ticket, accountEmail string) {
+	to := accountEmail
+	if ticket.DeliveryEmail != "" {
+		to = ticket.DeliveryEmail
+	}
+
+	subject, body, err := s.emails.Render(EmailTemplatePurchase, EmailData{Ticket: ticket, Event: &ticket.Event})
+	if err != nil {
+		log.Printf("failed to render purchase notice for ticket %s: %v", ticket.ID, err)
+		return
+	}
+	if err := s.notifier.Send(to, subject, body); err != nil {
+		log.Printf("failed to send purchase notice for ticket %s: %v", ticket.ID, err)
+	}
+}
+
+// reserveSeatsWithTx takes quantity seats from the event. The availability check is part
+// of the update, so availability never goes negative even if an earlier check raced.
+func reserveSeatsWithTx(tx *gorm.DB, eventID string, quantity int) error {
+	result := tx.Model(&entity.Event{}).
+		Where("id = ? AND available >= ?", eventID, quantity).
+		UpdateColumn("available", gorm.Expr("available - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("insufficient tickets available")
+	}
+	return nil
+}
+
+func (s *ticketService) purchaseTxOptions() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: s.rules.PurchaseIsolation}
+}
+
+// IssueCompTicket gives a user free tickets. Comps bypass the purchase cutoff, sale close,
+// paused sales and per-purchase cap, but still need an active event with enough seats.
+func (s *ticketService) IssueCompTicket(adminID string, req *entity.IssueCompTicketRequest) (*entity.Ticket, error) {
+	var ticket *entity.Ticket
+
+	if err := checkQuantity(req.Quantity); err != nil {
+		return nil, err
+	}
+
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Validate recipient
+		user, err := s.userRepo.GetByID(req.UserID)
+		if err != nil {
+			return err
+		}
+		if !user.IsActive {
+			return errors.New("user account is not active")
+		}
+
+		// Lock the event row (SELECT ... FOR UPDATE) to prevent race conditions
+		var event entity.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", req.EventID).First(&event).Error; err != nil {
 			return err
 		}
 
-		return nil
-	})
+		if !event.EventDate.After(time.Now()) {
+			return errors.New("event has already occurred")
+		}
+
+		if !event.IsAvailable() {
+			return errors.New("event is not available for booking")
+		}
+
+		if event.Available < req.Quantity {
+			return errors.New("insufficient tickets available")
+		}
+
+		ticket = &entity.Ticket{
+			UserID:       req.UserID,
+			EventID:      req.EventID,
+			Quantity:     req.Quantity,
+			TotalPrice:   0,
+			Status:       entity.TicketStatusActive,
+			StatusReason: reason,
+			IsComp:       true,
+			IssuedBy:     adminID,
+			PurchaseDate: time.Now(),
+		}
+
+		if err := tx.Create(ticket).Error; err != nil {
+			return err
+		}
+
+		return reserveSeatsWithTx(tx, req.EventID, req.Quantity)
+	}, s.purchaseTxOptions())
 
 	if err != nil {
 		return nil, err
 	}
+	s.activeCache.Invalidate()
 
-	// Return ticket with relations
 	return s.GetTicketByID(ticket.ID)
 }
 
+// QuoteTicket prices a purchase with the same checks as BuyTicket, without reserving seats
+func (s *ticketService) QuoteTicket(userID string, req *entity.BuyTicketRequest) (*entity.TicketQuote, error) {
+	if err := checkQuantity(req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if s.rules.MaxPerPurchase > 0 && req.Quantity > s.rules.MaxPerPurchase {
+		return nil, errors.New("quantity exceeds maximum tickets per purchase")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.New("user account is not active")
+	}
+
+	event, err := s.eventRepo.GetByID(req.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPurchasable(event, req.Quantity, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.priceTickets(event, req.Quantity)
+}
+
+// checkQuantity rejects quantities outside 1..maxTicketQuantity. A non-positive
+// quantity would add seats back instead of taking them.
+func checkQuantity(quantity int) error {
+	if quantity < 1 {
+		return errors.New("quantity must be at least 1")
+	}
+	if quantity > maxTicketQuantity {
+		return errors.New("quantity exceeds maximum tickets per purchase")
+	}
+	return nil
+}
+
+// checkPurchasable applies the event-side purchase rules shared by BuyTicket and QuoteTicket
+func (s *ticketService) checkPurchasable(event *entity.Event, quantity int, now time.Time) error {
+	// Past events get a distinct error from the purchase cutoff below
+	if !event.EventDate.After(now) {
+		return errors.New("event has already occurred")
+	}
+
+	// Check event availability
+	if !event.IsAvailable() {
+		return errors.New("event is not available for booking")
+	}
+
+	if event.SalesPaused {
+		return errors.New("sales are temporarily paused")
+	}
+
+	// Check if the organizer closed sales early
+	if event.IsSaleClosed(now) {
+		return errors.New("sales have closed")
+	}
+
+	// Check capacity
+	if event.Available < quantity {
+		return errors.New("insufficient tickets available")
+	}
+
+	// Check if event is far enough in the future
+	if event.EventDate.Before(now.Add(s.rules.PurchaseCutoff)) {
+		return errors.New("cannot purchase tickets this close to event start")
+	}
+
+	return nil
+}
+
+// priceTickets calculates the total price, keeping the tax and fee components separately
+func (s *ticketService) priceTickets(event *entity.Event, quantity int) (*entity.TicketQuote, error) {
+	subtotal := event.Price * float64(quantity)
+	taxAmount := math.Round(subtotal*event.TaxRate) / 100
+	fee := math.Round(subtotal*s.rules.ServiceFeePercent+s.rules.ServiceFeeFlat*float64(quantity)*100) / 100
+
+	// Huge prices or rates can overflow to Inf or lose cents, so refuse those totals
+	total := subtotal + taxAmount + fee
+	if math.IsNaN(total) || total > maxPurchaseTotal {
+		return nil, errors.New("purchase total is too large")
+	}
+
+	return &entity.TicketQuote{
+		EventID:    event.ID,
+		Quantity:   quantity,
+		UnitPrice:  event.Price,
+		Subtotal:   subtotal,
+		TaxRate:    event.TaxRate,
+		TaxAmount:  taxAmount,
+		Fee:        fee,
+		TotalPrice: total,
+		Available:  event.Available,
+	}, nil
+}
+
 func (s *ticketService) GetTicketByID(id string) (*entity.Ticket, error) {
-	return s.ticketRepo.GetByID(id)
+	ticket, err := s.ticketRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket.ApplyCancellationWindow(ticket.Event.EventDate, s.rules.CancellationCutoff, time.Now())
+	return ticket, nil
+}
+
+// applyCancellationWindows fills the refund fields of tickets loaded with their event
+func (s *ticketService) applyCancellationWindows(tickets []entity.Ticket) {
+	now := time.Now()
+	for i := range tickets {
+		tickets[i].ApplyCancellationWindow(tickets[i].Event.EventDate, s.rules.CancellationCutoff, now)
+	}
 }
 
-func (s *ticketService) GetUserTickets(userID string, pagination *entity.Pagination) ([]entity.Ticket, *entity.PaginationMeta, error) {
-	tickets, total, err := s.ticketRepo.GetByUserID(userID, pagination)
+func (s *ticketService) GetUserTickets(userID, eventID string, pagination *entity.Pagination, fields *entity.TicketFields) ([]entity.Ticket, *entity.PaginationMeta, error) {
+	if _, _, ok := fields.Columns(); !ok {
+		return nil, nil, errors.New("invalid fields parameter")
+	}
+
+	tickets, total, err := s.ticketRepo.GetByUserID(userID, eventID, pagination, fields)
 	if err != nil {
 		return nil, nil, err
 	}
+	s.applyCancellationWindows(tickets)
 
 	meta := &entity.PaginationMeta{
 		CurrentPage: pagination.Page,
@@ -133,11 +478,42 @@ func (s *ticketService) GetUserTickets(userID string, pagination *entity.Paginat
 	return tickets, meta, nil
 }
 
-func (s *ticketService) GetAllTickets(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter) ([]entity.Ticket, *entity.PaginationMeta, error) {
-	tickets, total, err := s.ticketRepo.GetAll(pagination, search, filter)
+func (s *ticketService) GetUserEventTickets(userID, eventID string) ([]entity.Ticket, error) {
+	// Validate event exists
+	event, err := s.eventRepo.GetByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.GetActiveByUserAndEvent(userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range tickets {
+		tickets[i].ApplyCancellationWindow(event.EventDate, s.rules.CancellationCutoff, now)
+	}
+
+	return tickets, nil
+}
+
+func (s *ticketService) GetAllTickets(pagination *entity.Pagination, search *entity.Search, filter *entity.TicketFilter, fields *entity.TicketFields) ([]entity.Ticket, *entity.PaginationMeta, error) {
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if _, _, ok := fields.Columns(); !ok {
+		return nil, nil, errors.New("invalid fields parameter")
+	}
+
+	tickets, total, err := s.ticketRepo.GetAll(pagination, search, filter, fields)
 	if err != nil {
 		return nil, nil, err
 	}
+	s.applyCancellationWindows(tickets)
 
 	meta := &entity.PaginationMeta{
 		CurrentPage: pagination.Page,
@@ -149,12 +525,55 @@ func (s *ticketService) GetAllTickets(pagination *entity.Pagination, search *ent
 	return tickets, meta, nil
 }
 
+// ExportTickets streams every ticket matching the filters to fn, one batch at a time
+func (s *ticketService) ExportTickets(search *entity.Search, filter *entity.TicketFilter, fn func(tickets []entity.Ticket) error) error {
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return s.ticketRepo.FindInBatches(search, filter, s.exportBatchSize, fn)
+}
+
+// ExportRefunds streams refunded tickets cancelled within the date range to fn, one batch at a time
+func (s *ticketService) ExportRefunds(filter *entity.DateRangeFilter, fn func(tickets []entity.Ticket) error) error {
+	if filter != nil && filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return errors.New("start date must be before end date")
+	}
+
+	return s.ticketRepo.FindRefundsInBatches(filter, s.exportBatchSize, fn)
+}
+
+// maxStatusReasonLength is the size of the tickets.status_reason column
+const maxStatusReasonLength = 255
+
+// normalizeReason trims a status reason and rejects one too long for its column.
+// Binding does not run the request's max tag, so this is the only check.
+func normalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxStatusReasonLength {
+		return "", errors.New("reason is too long")
+	}
+	return reason, nil
+}
+
 func (s *ticketService) UpdateTicketStatus(ticketID string, req *entity.UpdateTicketStatusRequest) (*entity.Ticket, error) {
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
 	ticket, err := s.ticketRepo.GetByID(ticketID)
 	if err != nil {
 		return nil, err
 	}
 
+	// Binding does not run validate tags, and the column accepts only known statuses
+	if req.Status != entity.TicketStatusUsed && req.Status != entity.TicketStatusCancelled && req.Status != entity.TicketStatusExpired {
+		return nil, errors.New("status must be used, cancelled or expired")
+	}
+
 	// Validate status transition
 	if ticket.Status == entity.TicketStatusCancelled {
 		return nil, errors.New("cannot update cancelled ticket")
@@ -164,24 +583,94 @@ func (s *ticketService) UpdateTicketStatus(ticketID string, req *entity.UpdateTi
 		return nil, errors.New("can only mark active tickets as used")
 	}
 
+	if req.Status == entity.TicketStatusExpired && ticket.Status != entity.TicketStatusActive {
+		return nil, errors.New("can only expire active tickets")
+	}
+
+	if req.Status == entity.TicketStatusUsed {
+		if err := s.checkInWindow(&ticket.Event, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Status == entity.TicketStatusCancelled {
+		return s.adminCancelTicket(ticket, reason)
+	}
+
 	// Update status
 	ticket.Status = req.Status
+	ticket.StatusReason = reason
+	if req.Status == entity.TicketStatusUsed {
+		now := time.Now()
+		ticket.CheckedInAt = &now
+	}
 	if err := s.ticketRepo.Update(ticket); err != nil {
 		return nil, err
 	}
 
+	ticket.ApplyCancellationWindow(ticket.Event.EventDate, s.rules.CancellationCutoff, time.Now())
+	return ticket, nil
+}
+
+// adminCancelTicket cancels a ticket from UpdateTicketStatus. Like the other cancellations
+// it locks the ticket and returns its seats to the event; no cancellation fee is charged.
+func (s *ticketService) adminCancelTicket(ticket *entity.Ticket, reason string) (*entity.Ticket, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var locked entity.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ticket.ID).First(&locked).Error; err != nil {
+			return err
+		}
+
+		// Re-checked under the lock so a concurrent cancellation cannot release the seats twice
+		if locked.Status == entity.TicketStatusCancelled {
+			return errors.New("cannot update cancelled ticket")
+		}
+
+		locked.StatusReason = reason
+		return cancelWithTx(tx, &locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	ticket.Status = entity.TicketStatusCancelled
+	ticket.StatusReason = reason
+	ticket.ApplyCancellationWindow(ticket.Event.EventDate, s.rules.CancellationCutoff, time.Now())
 	return ticket, nil
 }
 
-func (s *ticketService) CancelTicket(ticketID, userID string) (*entity.Ticket, error) {
+// checkInWindow rejects check-ins before the window opens or after the event's grace
+// period, which falls back to the global grace when the event sets none
+func (s *ticketService) checkInWindow(event *entity.Event, now time.Time) error {
+	if s.rules.CheckInOpensBefore > 0 && now.Before(event.EventDate.Add(-s.rules.CheckInOpensBefore)) {
+		return errors.New("check-in window not yet open")
+	}
+
+	if event.CheckInGrace != nil {
+		if now.After(event.EventDate.Add(time.Duration(*event.CheckInGrace) * time.Minute)) {
+			return errors.New("check-in window closed")
+		}
+	} else if s.rules.CheckInGrace > 0 && now.After(event.EventDate.Add(s.rules.CheckInGrace)) {
+		return errors.New("check-in window closed")
+	}
+
+	return nil
+}
+
+func (s *ticketService) CancelTicket(ticketID, userID string, req *entity.CancelTicketRequest) (*entity.Ticket, error) {
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
 	var ticket *entity.Ticket
-	var err error
 
 	// Start transaction
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		// Get ticket with SELECT FOR UPDATE
+		// Lock the ticket row (SELECT ... FOR UPDATE)
 		var ticketEntity entity.Ticket
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", ticketID).First(&ticketEntity).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ticketID).First(&ticketEntity).Error; err != nil {
 			return err
 		}
 		ticket = &ticketEntity
@@ -191,33 +680,79 @@ func (s *ticketService) CancelTicket(ticketID, userID string) (*entity.Ticket, e
 			return errors.New("you can only cancel your own tickets")
 		}
 
+		// Get event to check timing
+		var event entity.Event
+		if err := tx.Where("id = ?", ticket.EventID).First(&event).Error; err != nil {
+			return err
+		}
+
+		// Cancelling twice succeeds without changes so clients can safely retry
+		if ticket.Status == entity.TicketStatusCancelled {
+			ticket.ApplyCancellationWindow(event.EventDate, s.rules.CancellationCutoff, time.Now())
+			return nil
+		}
+
 		// Check if ticket can be cancelled
 		if !ticket.CanBeCancelled() {
 			return errors.New("ticket cannot be cancelled")
 		}
 
-		// Get event to check timing
-		var event entity.Event
-		if err := tx.Where("id = ?", ticket.EventID).First(&event).Error; err != nil {
+		if event.EventDate.Before(time.Now().Add(s.rules.CancellationCutoff)) {
+			return errors.New("cannot cancel tickets this close to event start")
+		}
+
+		ticket.StatusReason = reason
+		ticket.CancellationFee = s.rules.CancellationFee(ticket.TotalPrice, time.Until(event.EventDate))
+		if err := cancelWithTx(tx, ticket); err != nil {
 			return err
 		}
+		ticket.ApplyCancellationWindow(event.EventDate, s.rules.CancellationCutoff, time.Now())
 
-		if event.EventDate.Before(time.Now().Add(2 * time.Hour)) {
-			return errors.New("cannot cancel tickets within 2 hours of event start")
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	s.activeCache.Invalidate()
+
+	return ticket, nil
+}
+
+// ForceCancelTicket lets an admin cancel any active ticket, including past the
+// cancellation cutoff. The reason is required and the cancellation is logged.
+func (s *ticketService) ForceCancelTicket(ticketID, adminID string, req *entity.ForceCancelTicketRequest) (*entity.Ticket, error) {
+	reason, err := normalizeReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	var ticket *entity.Ticket
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var ticketEntity entity.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", ticketID).First(&ticketEntity).Error; err != nil {
+			return err
 		}
+		ticket = &ticketEntity
 
-		// Update ticket status within transaction
-		ticket.Status = entity.TicketStatusCancelled
-		if err := tx.Save(ticket).Error; err != nil {
+		var event entity.Event
+		if err := tx.Where("id = ?", ticket.EventID).First(&event).Error; err != nil {
 			return err
 		}
 
-		// Return tickets to event availability within transaction (negative quantity to add back)
-		if err := tx.Model(&entity.Event{}).
-			Where("id = ?", ticket.EventID).
-			UpdateColumn("available", gorm.Expr("available + ?", ticket.Quantity)).Error; err != nil {
+		if !ticket.CanBeCancelled() {
+			return errors.New("ticket cannot be cancelled")
+		}
+
+		ticket.StatusReason = reason
+		ticket.CancelledBy = adminID
+		if err := cancelWithTx(tx, ticket); err != nil {
 			return err
 		}
+		ticket.ApplyCancellationWindow(event.EventDate, s.rules.CancellationCutoff, time.Now())
 
 		return nil
 	})
@@ -225,10 +760,68 @@ func (s *ticketService) CancelTicket(ticketID, userID string) (*entity.Ticket, e
 	if err != nil {
 		return nil, err
 	}
+	s.activeCache.Invalidate()
 
+	log.Printf("audit: admin %s force-cancelled ticket %s (event %s, user %s, quantity %d): %s",
+		adminID, ticket.ID, ticket.EventID, ticket.UserID, ticket.Quantity, reason)
 	return ticket, nil
 }
 
+// cancelWithTx marks a locked ticket cancelled and returns its seats to the event,
+// never exceeding capacity
+func cancelWithTx(tx *gorm.DB, ticket *entity.Ticket) error {
+	ticket.Status = entity.TicketStatusCancelled
+	if err := tx.Save(ticket).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entity.Event{}).
+		Where("id = ?", ticket.EventID).
+		UpdateColumn("available", gorm.Expr("LEAST(available + ?, capacity)", ticket.Quantity)).Error
+}
+
+// SendEventReminders notifies holders of tickets for events starting within window.
+// Each ticket is claimed before sending, so holders are reminded at most once even
+// when several instances run the job; a failed send is logged and not retried.
+func (s *ticketService) SendEventReminders(window time.Duration) (int, error) {
+	now := time.Now()
+	tickets, err := s.ticketRepo.GetDueForReminder(now, now.Add(window))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range tickets {
+		ticket := &tickets[i]
+
+		claimed, err := s.ticketRepo.MarkReminded(ticket.ID, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		to := ticket.User.Email
+		if ticket.DeliveryEmail != "" {
+			to = ticket.DeliveryEmail
+		}
+
+		subject, body, err := s.emails.Render(EmailTemplateReminder, EmailData{User: &ticket.User, Ticket: ticket, Event: &ticket.Event})
+		if err != nil {
+			log.Printf("failed to render reminder for ticket %s: %v", ticket.ID, err)
+			continue
+		}
+		if err := s.notifier.Send(to, subject, body); err != nil {
+			log.Printf("failed to send reminder for ticket %s: %v", ticket.ID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
 func (s *ticketService) GetTicketStats() (*entity.ReportSummary, error) {
 	return s.ticketRepo.GetTicketStats()
 }
@@ -241,4 +834,227 @@ func (s *ticketService) GetEventReport(eventID string) (*entity.EventReport, err
 	}
 
 	return s.ticketRepo.GetEventReport(eventID)
-} 
\ No newline at end of file
+}
+
+func (s *ticketService) GetDetailedEventReport(eventID string) (*entity.DetailedEventReport, error) {
+	report, err := s.GetEventReport(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	gross, refunds, err := s.ticketRepo.GetEventRefundTotals(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown, err := s.ticketRepo.GetEventStatusBreakdown(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.DetailedEventReport{
+		EventReport:     *report,
+		GrossRevenue:    gross,
+		RefundTotal:     refunds,
+		NetRevenue:      gross - refunds,
+		StatusBreakdown: breakdown,
+	}, nil
+}
+
+func (s *ticketService) GetUserSpending(userID string) (*entity.UserSpendingSummary, error) {
+	byStatus, err := s.ticketRepo.GetUserSpendingByStatus(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byEvent, err := s.ticketRepo.GetUserSpendingByEvent(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.UserSpendingSummary{
+		ByStatus: byStatus,
+		ByEvent:  byEvent,
+	}
+	for _, status := range byStatus {
+		if status.Status == entity.TicketStatusCancelled {
+			continue
+		}
+		summary.TotalSpent += status.Amount
+		summary.TicketCount += status.Count
+	}
+	summary.TotalSpent = math.Round(summary.TotalSpent*100) / 100
+
+	return summary, nil
+}
+
+// GetEventsImpact previews, without changing anything, the tickets and refunds that
+// cancelling or deleting each of the given events would involve
+func (s *ticketService) GetEventsImpact(ids []string) (*entity.EventsImpactSummary, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids are required")
+	}
+	if len(ids) > entity.MaxPageLimit {
+		return nil, errors.New("too many ids in one request")
+	}
+
+	events, err := s.eventRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.ticketRepo.GetActiveTicketTotals(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byEvent := make(map[string]entity.EventTicketTotals, len(totals))
+	for _, total := range totals {
+		byEvent[total.EventID] = total
+	}
+	byID := make(map[string]entity.Event, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+
+	summary := &entity.EventsImpactSummary{Events: []entity.EventImpact{}}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		event, ok := byID[id]
+		if !ok {
+			summary.MissingIDs = append(summary.MissingIDs, id)
+			continue
+		}
+
+		total := byEvent[id]
+		summary.Events = append(summary.Events, entity.EventImpact{
+			EventID:       event.ID,
+			Name:          event.Name,
+			Status:        event.Status,
+			ActiveTickets: total.Tickets,
+			SeatsHeld:     total.Seats,
+			RefundTotal:   math.Round(total.RefundTotal*100) / 100,
+			Cancellable:   event.Status != entity.EventStatusCompleted && event.Status != entity.EventStatusCancelled,
+			Deletable:     event.Capacity == event.Available,
+		})
+		summary.ActiveTickets += total.Tickets
+		summary.RefundTotal += total.RefundTotal
+	}
+	summary.RefundTotal = math.Round(summary.RefundTotal*100) / 100
+
+	return summary, nil
+}
+
+// maxTimelinePoints caps a sales timeline, e.g. about six weeks of hourly buckets
+const maxTimelinePoints = 1000
+
+// GetEventSalesTimeline returns the event's sales per interval from the bucket of its
+// creation up to the current one, including buckets without sales
+func (s *ticketService) GetEventSalesTimeline(eventID, interval string) (*entity.EventSalesTimeline, error) {
+	if interval == "" {
+		interval = "day"
+	}
+	if interval != "hour" && interval != "day" && interval != "week" {
+		return nil, errors.New("interval must be hour, day or week")
+	}
+
+	event, err := s.eventRepo.GetByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := s.ticketRepo.GetEventSalesTimeline(eventID, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-bucket in Go so database rows line up with the generated periods
+	start := truncateToInterval(event.CreatedAt, interval)
+	bySlot := make(map[int64]entity.SalesTimelinePoint, len(sales))
+	for _, sale := range sales {
+		period := truncateToInterval(sale.Period, interval)
+		if period.Before(start) {
+			start = period
+		}
+		point := bySlot[period.Unix()]
+		point.TicketsSold += sale.TicketsSold
+		point.Revenue += sale.Revenue
+		bySlot[period.Unix()] = point
+	}
+
+	end := truncateToInterval(time.Now(), interval)
+	points := []entity.SalesTimelinePoint{}
+	for period := start; !period.After(end); period = nextInterval(period, interval) {
+		if len(points) == maxTimelinePoints {
+			return nil, errors.New("timeline has too many points, use a coarser interval")
+		}
+		point := bySlot[period.Unix()]
+		point.Period = period
+		point.Revenue = math.Round(point.Revenue*100) / 100
+		points = append(points, point)
+	}
+
+	return &entity.EventSalesTimeline{
+		EventID:  eventID,
+		Interval: interval,
+		Points:   points,
+	}, nil
+}
+
+// truncateToInterval returns the start of the hour, day or week (from Monday) containing t, in local time
+func truncateToInterval(t time.Time, interval string) time.Time {
+	t = t.In(time.Local)
+	switch interval {
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.Local)
+	case "week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+}
+
+func nextInterval(period time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return truncateToInterval(period.Add(time.Hour), interval)
+	case "week":
+		return period.AddDate(0, 0, 7)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
+
+func (s *ticketService) GetReportByCategory(filter *entity.DateRangeFilter) ([]entity.CategoryReport, error) {
+	if filter != nil && filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	return s.ticketRepo.GetReportByCategory(filter)
+}
+
+func (s *ticketService) GetReportByLocation(pagination *entity.Pagination, filter *entity.DateRangeFilter) ([]entity.LocationReport, *entity.PaginationMeta, error) {
+	if filter != nil && filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return nil, nil, errors.New("start date must be before end date")
+	}
+
+	reports, total, err := s.ticketRepo.GetReportByLocation(pagination, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &entity.PaginationMeta{
+		CurrentPage: pagination.Page,
+		TotalItems:  total,
+		Limit:       pagination.GetLimit(),
+		TotalPages:  int((total + int64(pagination.GetLimit()) - 1) / int64(pagination.GetLimit())),
+	}
+
+	return reports, meta, nil
+}
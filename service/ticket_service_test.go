@@ -0,0 +1,618 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"ticketing-system/entity"
+	"ticketing-system/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TestSeatAccountingProperty runs random purchases, comps and cancellations through every
+// path that moves seats and checks after each step that no seat is lost or sold twice
+func TestSeatAccountingProperty(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	var users []*entity.User
+	for i := 0; i < 3; i++ {
+		users = append(users, createTestUser(t, db, fmt.Sprintf("user%d@example.com", i)))
+	}
+	admin := createTestUser(t, db, "admin@example.com")
+	event := createTestEvent(t, db, 12, time.Now().Add(30*24*time.Hour))
+
+	rng := rand.New(rand.NewSource(1))
+	var tickets []*entity.Ticket
+
+	for step := 0; step < 200; step++ {
+		user := users[rng.Intn(len(users))]
+		quantity := 1 + rng.Intn(4)
+
+		switch op := rng.Intn(6); {
+		case op == 0 || len(tickets) == 0:
+			if ticket, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: quantity}); err == nil {
+				tickets = append(tickets, ticket)
+			}
+		case op == 1:
+			if ticket, err := svc.IssueCompTicket(admin.ID, &entity.IssueCompTicketRequest{UserID: user.ID, EventID: event.ID, Quantity: quantity}); err == nil {
+				tickets = append(tickets, ticket)
+			}
+		case op == 2:
+			ticket := tickets[rng.Intn(len(tickets))]
+			svc.CancelTicket(ticket.ID, ticket.UserID, &entity.CancelTicketRequest{})
+		case op == 3:
+			ticket := tickets[rng.Intn(len(tickets))]
+			svc.ForceCancelTicket(ticket.ID, admin.ID, &entity.ForceCancelTicketRequest{Reason: "test"})
+		case op == 4:
+			ticket := tickets[rng.Intn(len(tickets))]
+			svc.UpdateTicketStatus(ticket.ID, &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusCancelled})
+		default:
+			ticket := tickets[rng.Intn(len(tickets))]
+			svc.UpdateTicketStatus(ticket.ID, &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusExpired})
+		}
+
+		assertSeatsBalance(t, db, event.ID)
+	}
+}
+
+func TestUpdateTicketStatusCancelReturnsSeats(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	user := createTestUser(t, db, "buyer@example.com")
+	event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+	ticket, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 3})
+	if err != nil {
+		t.Fatalf("BuyTicket: %v", err)
+	}
+
+	updated, err := svc.UpdateTicketStatus(ticket.ID, &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusCancelled, Reason: "admin"})
+	if err != nil {
+		t.Fatalf("UpdateTicketStatus: %v", err)
+	}
+	if updated.Status != entity.TicketStatusCancelled {
+		t.Fatalf("status = %s, want cancelled", updated.Status)
+	}
+
+	var reloaded entity.Event
+	db.Where("id = ?", event.ID).First(&reloaded)
+	if reloaded.Available != 10 {
+		t.Fatalf("available = %d, want 10", reloaded.Available)
+	}
+
+	if _, err := svc.UpdateTicketStatus(ticket.ID, &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusCancelled}); err == nil {
+		t.Fatal("cancelling a cancelled ticket again succeeded")
+	}
+	assertSeatsBalance(t, db, event.ID)
+}
+
+// TestConcurrentPurchasesNeverOversell races more buyers than seats
+func TestConcurrentPurchasesNeverOversell(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	const seats, buyers = 5, 20
+	event := createTestEvent(t, db, seats, time.Now().Add(7*24*time.Hour))
+
+	var users []*entity.User
+	for i := 0; i < buyers; i++ {
+		users = append(users, createTestUser(t, db, fmt.Sprintf("racer%d@example.com", i)))
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sold := 0
+	for _, user := range users {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+			if _, err := svc.BuyTicket(userID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1}); err == nil {
+				mu.Lock()
+				sold++
+				mu.Unlock()
+			}
+		}(user.ID)
+	}
+	wg.Wait()
+
+	if sold != seats {
+		t.Fatalf("sold %d tickets, want %d", sold, seats)
+	}
+	assertSeatsBalance(t, db, event.ID)
+}
+
+func TestDuplicatePurchaseWindow(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{DuplicateWindow: time.Minute})
+
+	user := createTestUser(t, db, "repeat@example.com")
+	other := createTestUser(t, db, "other@example.com")
+	event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+	first, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1})
+	if err != nil {
+		t.Fatalf("first purchase: %v", err)
+	}
+
+	_, err = svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1})
+	if err == nil || err.Error() != "duplicate purchase, please wait before buying again" {
+		t.Fatalf("repeat purchase error = %v, want duplicate purchase", err)
+	}
+
+	if _, err := svc.BuyTicket(other.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1}); err != nil {
+		t.Fatalf("another user's purchase: %v", err)
+	}
+
+	// A cancelled purchase does not block buying again
+	if _, err := svc.CancelTicket(first.ID, user.ID, &entity.CancelTicketRequest{}); err != nil {
+		t.Fatalf("CancelTicket: %v", err)
+	}
+	if _, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1}); err != nil {
+		t.Fatalf("purchase after cancelling: %v", err)
+	}
+}
+
+// TestConcurrentDuplicatePurchases sends the same purchase several times at once, as a
+// double-clicked button would; the event row lock must let exactly one through
+func TestConcurrentDuplicatePurchases(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{DuplicateWindow: time.Minute})
+
+	user := createTestUser(t, db, "double@example.com")
+	event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1}); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d concurrent duplicate purchases succeeded, want 1", succeeded)
+	}
+	assertSeatsBalance(t, db, event.ID)
+}
+
+func TestParseIsolationLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    sql.IsolationLevel
+		wantErr bool
+	}{
+		{"", sql.LevelDefault, false},
+		{"default", sql.LevelDefault, false},
+		{"read_committed", sql.LevelReadCommitted, false},
+		{" Repeatable_Read ", sql.LevelRepeatableRead, false},
+		{"SERIALIZABLE", sql.LevelSerializable, false},
+		{"read_uncommitted", sql.LevelDefault, true},
+		{"snapshot", sql.LevelDefault, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseIsolationLevel(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIsolationLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseIsolationLevel(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPurchaseIsolationReachesDatabase checks that the configured level is the one the
+// purchase transaction actually runs at, and that racing purchases at each level never
+// oversell. At repeatable_read and serializable PostgreSQL aborts some racing purchases
+// with a serialization error instead of making them wait, and MySQL may abort them as
+// deadlocks.
+func TestPurchaseIsolationReachesDatabase(t *testing.T) {
+	db := openTestDB(t)
+
+	// Each driver reports the level in its own spelling
+	query := "SHOW transaction_isolation"
+	levels := map[string]string{
+		"read_committed":  "read committed",
+		"repeatable_read": "repeatable read",
+		"serializable":    "serializable",
+	}
+	if db.Dialector.Name() == "mysql" {
+		query = "SELECT @@transaction_isolation"
+		levels = map[string]string{
+			"read_committed":  "READ-COMMITTED",
+			"repeatable_read": "REPEATABLE-READ",
+			"serializable":    "SERIALIZABLE",
+		}
+	}
+
+	for name, want := range levels {
+		t.Run(name, func(t *testing.T) {
+			level, err := ParseIsolationLevel(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc := &ticketService{db: db, rules: TicketRules{PurchaseIsolation: level}}
+
+			var got string
+			err = db.Transaction(func(tx *gorm.DB) error {
+				return tx.Raw(query).Scan(&got).Error
+			}, svc.purchaseTxOptions())
+			if err != nil {
+				t.Fatalf("read isolation: %v", err)
+			}
+			if got != want {
+				t.Fatalf("transaction_isolation = %q, want %q", got, want)
+			}
+
+			racePurchases(t, db, TicketRules{PurchaseIsolation: level})
+		})
+	}
+}
+
+func racePurchases(t *testing.T, db *gorm.DB, rules TicketRules) {
+	t.Helper()
+
+	svc := newTestTicketService(t, db, &recordingNotifier{}, rules)
+	const seats, buyers = 3, 10
+	event := createTestEvent(t, db, seats, time.Now().Add(7*24*time.Hour))
+
+	var users []*entity.User
+	for i := 0; i < buyers; i++ {
+		users = append(users, createTestUser(t, db, fmt.Sprintf("%s-%d@example.com", event.ID, i)))
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sold := 0
+	for _, user := range users {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+			if _, err := svc.BuyTicket(userID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1}); err == nil {
+				mu.Lock()
+				sold++
+				mu.Unlock()
+			}
+		}(user.ID)
+	}
+	wg.Wait()
+
+	if sold < 1 || sold > seats {
+		t.Fatalf("sold %d tickets, want between 1 and %d", sold, seats)
+	}
+	assertSeatsBalance(t, db, event.ID)
+}
+
+// TestReportRevenueIncludesCancellationFees checks the event report agrees with the
+// detailed report's net revenue once a cancellation fee has been kept
+func TestReportRevenueIncludesCancellationFees(t *testing.T) {
+	db := openTestDB(t)
+	tiers, err := ParseCancellationFeeTiers("0:50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{CancellationFeeTiers: tiers})
+
+	user := createTestUser(t, db, "fees@example.com")
+	event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+	kept, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 1})
+	if err != nil {
+		t.Fatalf("BuyTicket: %v", err)
+	}
+	cancelled, err := svc.BuyTicket(user.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 2})
+	if err != nil {
+		t.Fatalf("BuyTicket: %v", err)
+	}
+	if _, err := svc.CancelTicket(cancelled.ID, user.ID, &entity.CancelTicketRequest{}); err != nil {
+		t.Fatalf("CancelTicket: %v", err)
+	}
+
+	report, err := svc.GetDetailedEventReport(event.ID)
+	if err != nil {
+		t.Fatalf("GetDetailedEventReport: %v", err)
+	}
+
+	wantFees := cancelled.TotalPrice / 2
+	if report.CancellationFees != wantFees {
+		t.Errorf("cancellation_fees = %v, want %v", report.CancellationFees, wantFees)
+	}
+	if report.Revenue != kept.TotalPrice+wantFees {
+		t.Errorf("revenue = %v, want %v", report.Revenue, kept.TotalPrice+wantFees)
+	}
+	if report.Revenue != report.NetRevenue {
+		t.Errorf("revenue %v disagrees with net revenue %v", report.Revenue, report.NetRevenue)
+	}
+
+	summary, err := svc.GetTicketStats()
+	if err != nil {
+		t.Fatalf("GetTicketStats: %v", err)
+	}
+	if summary.TotalRevenue != report.Revenue || summary.CancellationFees != wantFees {
+		t.Errorf("summary revenue %v, fees %v; want %v, %v", summary.TotalRevenue, summary.CancellationFees, report.Revenue, wantFees)
+	}
+}
+
+func TestCheckPurchasable(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc := &ticketService{rules: TicketRules{PurchaseCutoff: time.Hour}}
+
+	event := func(edit func(e *entity.Event)) *entity.Event {
+		e := &entity.Event{Status: entity.EventStatusActive, Capacity: 10, Available: 5, EventDate: now.Add(24 * time.Hour)}
+		if edit != nil {
+			edit(e)
+		}
+		return e
+	}
+	closed := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		event    *entity.Event
+		quantity int
+		wantErr  string
+	}{
+		{"purchasable", event(nil), 5, ""},
+		{"started", event(func(e *entity.Event) { e.EventDate = now }), 1, "event has already occurred"},
+		{"in the past", event(func(e *entity.Event) { e.EventDate = now.Add(-time.Hour) }), 1, "event has already occurred"},
+		{"past and cancelled", event(func(e *entity.Event) {
+			e.EventDate = now.Add(-time.Hour)
+			e.Status = entity.EventStatusCancelled
+		}), 1, "event has already occurred"},
+		{"draft", event(func(e *entity.Event) { e.Status = entity.EventStatusDraft }), 1, "event is not available for booking"},
+		{"sold out", event(func(e *entity.Event) { e.Available = 0 }), 1, "event is not available for booking"},
+		{"paused", event(func(e *entity.Event) { e.SalesPaused = true }), 1, "sales are temporarily paused"},
+		{"sale closed", event(func(e *entity.Event) { e.SaleEndsAt = &closed }), 1, "sales have closed"},
+		{"too many", event(nil), 6, "insufficient tickets available"},
+		{"inside cutoff", event(func(e *entity.Event) { e.EventDate = now.Add(30 * time.Minute) }), 1, "cannot purchase tickets this close to event start"},
+		{"at cutoff", event(func(e *entity.Event) { e.EventDate = now.Add(time.Hour) }), 1, ""},
+	}
+
+	for _, tt := range tests {
+		err := svc.checkPurchasable(tt.event, tt.quantity, now)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReportsSplitPaidAndCompTickets(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestTicketService(t, db, &recordingNotifier{}, TicketRules{})
+
+	buyer := createTestUser(t, db, "paid@example.com")
+	guest := createTestUser(t, db, "comp@example.com")
+	admin := createTestUser(t, db, "admin@example.com")
+	event := createTestEvent(t, db, 10, time.Now().Add(7*24*time.Hour))
+
+	paid, err := svc.BuyTicket(buyer.ID, &entity.BuyTicketRequest{EventID: event.ID, Quantity: 2})
+	if err != nil {
+		t.Fatalf("BuyTicket: %v", err)
+	}
+	if _, err := svc.IssueCompTicket(admin.ID, &entity.IssueCompTicketRequest{UserID: guest.ID, EventID: event.ID, Quantity: 3}); err != nil {
+		t.Fatalf("IssueCompTicket: %v", err)
+	}
+
+	report, err := svc.GetEventReport(event.ID)
+	if err != nil {
+		t.Fatalf("GetEventReport: %v", err)
+	}
+	if report.TicketsSold != 2 || report.PaidTickets != 1 || report.CompTickets != 1 {
+		t.Errorf("event report sold/paid/comp = %d/%d/%d, want 2/1/1", report.TicketsSold, report.PaidTickets, report.CompTickets)
+	}
+	if report.Revenue != paid.TotalPrice {
+		t.Errorf("event report revenue = %v, want %v from the paid ticket only", report.Revenue, paid.TotalPrice)
+	}
+
+	summary, err := svc.GetTicketStats()
+	if err != nil {
+		t.Fatalf("GetTicketStats: %v", err)
+	}
+	if summary.TotalTicketsSold != 2 || summary.PaidTickets != 1 || summary.CompTickets != 1 {
+		t.Errorf("summary sold/paid/comp = %d/%d/%d, want 2/1/1", summary.TotalTicketsSold, summary.PaidTickets, summary.CompTickets)
+	}
+	if summary.TotalRevenue != paid.TotalPrice {
+		t.Errorf("summary revenue = %v, want %v", summary.TotalRevenue, paid.TotalPrice)
+	}
+}
+
+func TestCheckQuantity(t *testing.T) {
+	tests := []struct {
+		quantity int
+		wantErr  string
+	}{
+		{-1, "quantity must be at least 1"},
+		{0, "quantity must be at least 1"},
+		{1, ""},
+		{maxTicketQuantity, ""},
+		{maxTicketQuantity + 1, "quantity exceeds maximum tickets per purchase"},
+	}
+
+	for _, tt := range tests {
+		err := checkQuantity(tt.quantity)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("checkQuantity(%d) unexpected error %v", tt.quantity, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("checkQuantity(%d) error = %v, want %q", tt.quantity, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPurchaseLimit(t *testing.T) {
+	tests := []struct {
+		max  int
+		want int
+	}{
+		{0, maxTicketQuantity},
+		{-5, maxTicketQuantity},
+		{10, 10},
+		{maxTicketQuantity + 500, maxTicketQuantity},
+	}
+
+	for _, tt := range tests {
+		if got := (TicketRules{MaxPerPurchase: tt.max}).PurchaseLimit(); got != tt.want {
+			t.Errorf("PurchaseLimit with MaxPerPurchase %d = %d, want %d", tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestPriceTickets(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     TicketRules
+		price     float64
+		taxRate   float64
+		quantity  int
+		wantTax   float64
+		wantFee   float64
+		wantTotal float64
+		wantErr   bool
+	}{
+		{name: "plain", price: 25, quantity: 2, wantTotal: 50},
+		{name: "free", price: 0, taxRate: 10, quantity: 3, wantTotal: 0},
+		{name: "tax rounded to cents", price: 9.99, taxRate: 7.5, quantity: 3, wantTax: 2.25, wantTotal: 32.22},
+		{
+			name:  "percent and flat fee",
+			rules: TicketRules{ServiceFeePercent: 5, ServiceFeeFlat: 1.5},
+			price: 20, taxRate: 10, quantity: 2,
+			wantTax: 4, wantFee: 5, wantTotal: 49,
+		},
+		{name: "largest allowed quantity", price: 1e6, quantity: maxTicketQuantity, wantTotal: 1e9},
+		{name: "total above the cap", price: 1e10, quantity: maxTicketQuantity, wantErr: true},
+		{name: "tax overflows", price: 1e300, taxRate: 1e300, quantity: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		svc := &ticketService{rules: tt.rules}
+		quote, err := svc.priceTickets(&entity.Event{Price: tt.price, TaxRate: tt.taxRate, Available: 5}, tt.quantity)
+		if tt.wantErr {
+			if err == nil || err.Error() != "purchase total is too large" {
+				t.Errorf("%s: error = %v, want purchase total is too large", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if quote.TaxAmount != tt.wantTax || quote.Fee != tt.wantFee || quote.TotalPrice != tt.wantTotal {
+			t.Errorf("%s: tax/fee/total = %v/%v/%v, want %v/%v/%v",
+				tt.name, quote.TaxAmount, quote.Fee, quote.TotalPrice, tt.wantTax, tt.wantFee, tt.wantTotal)
+		}
+		if quote.Subtotal+quote.TaxAmount+quote.Fee != quote.TotalPrice {
+			t.Errorf("%s: total %v is not subtotal + tax + fee", tt.name, quote.TotalPrice)
+		}
+	}
+}
+
+func TestNormalizeReason(t *testing.T) {
+	tests := []struct {
+		reason  string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"  changed plans \n", "changed plans", false},
+		{strings.Repeat("a", maxStatusReasonLength), strings.Repeat("a", maxStatusReasonLength), false},
+		{" " + strings.Repeat("a", maxStatusReasonLength) + " ", strings.Repeat("a", maxStatusReasonLength), false},
+		{strings.Repeat("a", maxStatusReasonLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeReason(tt.reason)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeReason(%q) error = %v, wantErr %v", tt.reason, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("normalizeReason(%q) = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+
+	// Cancellations reject a long reason before touching the database
+	svc := &ticketService{}
+	long := strings.Repeat("a", maxStatusReasonLength+1)
+	if _, err := svc.CancelTicket("ticket", "user", &entity.CancelTicketRequest{Reason: long}); err == nil || err.Error() != "reason is too long" {
+		t.Errorf("CancelTicket error = %v, want reason is too long", err)
+	}
+	if _, err := svc.UpdateTicketStatus("ticket", &entity.UpdateTicketStatusRequest{Status: entity.TicketStatusCancelled, Reason: long}); err == nil || err.Error() != "reason is too long" {
+		t.Errorf("UpdateTicketStatus error = %v, want reason is too long", err)
+	}
+	if _, err := svc.IssueCompTicket("admin", &entity.IssueCompTicketRequest{UserID: "user", EventID: "event", Quantity: 1, Reason: long}); err == nil || err.Error() != "reason is too long" {
+		t.Errorf("IssueCompTicket error = %v, want reason is too long", err)
+	}
+}
+
+// timelineEventRepository serves one event for GetEventSalesTimeline
+type timelineEventRepository struct {
+	repository.EventRepository
+	event *entity.Event
+}
+
+func (r *timelineEventRepository) GetByID(id string) (*entity.Event, error) {
+	return r.event, nil
+}
+
+// timelineTicketRepository returns no sales for GetEventSalesTimeline
+type timelineTicketRepository struct {
+	repository.TicketRepository
+}
+
+func (r *timelineTicketRepository) GetEventSalesTimeline(eventID, interval string) ([]entity.SalesTimelinePoint, error) {
+	return nil, nil
+}
+
+func TestGetEventSalesTimelinePointCap(t *testing.T) {
+	tests := []struct {
+		interval string
+		age      time.Duration
+		wantErr  bool
+	}{
+		{"hour", 24 * time.Hour, false},
+		{"hour", 30 * 24 * time.Hour, false},
+		{"hour", 60 * 24 * time.Hour, true},
+		{"day", 60 * 24 * time.Hour, false},
+		{"day", 5 * 365 * 24 * time.Hour, true},
+		{"week", 5 * 365 * 24 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		svc := &ticketService{
+			eventRepo:  &timelineEventRepository{event: &entity.Event{CreatedAt: time.Now().Add(-tt.age)}},
+			ticketRepo: &timelineTicketRepository{},
+		}
+
+		timeline, err := svc.GetEventSalesTimeline("event", tt.interval)
+		if tt.wantErr {
+			if err == nil || err.Error() != "timeline has too many points, use a coarser interval" {
+				t.Errorf("%s over %s: error = %v, want too many points", tt.interval, tt.age, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s over %s: %v", tt.interval, tt.age, err)
+			continue
+		}
+		if len(timeline.Points) == 0 || len(timeline.Points) > maxTimelinePoints {
+			t.Errorf("%s over %s: %d points", tt.interval, tt.age, len(timeline.Points))
+		}
+	}
+}
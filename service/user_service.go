@@ -1,38 +1,70 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"log"
+	"net/mail"
 	"ticketing-system/entity"
 	"ticketing-system/repository"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserService interface {
 	Register(req *entity.RegisterRequest) (*entity.User, error)
 	Login(req *entity.LoginRequest) (*entity.LoginResponse, error)
+	RefreshToken(token string) (*entity.LoginResponse, error)
 	GetProfile(userID string) (*entity.User, error)
-	UpdateProfile(userID string, user *entity.User) (*entity.User, error)
-	GetAllUsers(pagination *entity.Pagination, search *entity.Search) ([]entity.User, *entity.PaginationMeta, error)
+	UpdateProfile(userID string, req *entity.UpdateProfileRequest) (*entity.User, error)
+	RequestEmailChange(userID, newEmail string) (*entity.User, error)
+	ConfirmEmailChange(token string) (*entity.User, error)
+	GetAllUsers(pagination *entity.Pagination, search *entity.Search, filter *entity.UserFilter) ([]entity.User, *entity.PaginationMeta, error)
 	DeleteUser(userID string) error
 	GenerateJWT(user *entity.User) (string, error)
 	ValidateJWT(tokenString string) (*entity.User, error)
 }
 
+// EmailChangeSettings controls the confirmation sent when a user changes email
+type EmailChangeSettings struct {
+	ConfirmURL string        // the token is appended to this URL in the confirmation link
+	TokenTTL   time.Duration // how long the confirmation link stays valid
+}
+
 type userService struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
-	jwtExpiry time.Duration
+	userRepo       repository.UserRepository
+	hasher         PasswordHasher
+	notifier       Notifier
+	emails         *EmailTemplates
+	emailChange    EmailChangeSettings
+	jwtSecret      string
+	jwtExpiry      time.Duration
+	jwtAdminExpiry time.Duration
+	refreshExpiry  time.Duration
 }
 
-func NewUserService(userRepo repository.UserRepository, jwtSecret string, jwtExpiry time.Duration) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	hasher PasswordHasher,
+	notifier Notifier,
+	emails *EmailTemplates,
+	emailChange EmailChangeSettings,
+	jwtSecret string,
+	jwtExpiry, jwtAdminExpiry, refreshExpiry time.Duration,
+) UserService {
 	return &userService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpiry: jwtExpiry,
+		userRepo:       userRepo,
+		hasher:         hasher,
+		notifier:       notifier,
+		emails:         emails,
+		emailChange:    emailChange,
+		jwtSecret:      jwtSecret,
+		jwtExpiry:      jwtExpiry,
+		jwtAdminExpiry: jwtAdminExpiry,
+		refreshExpiry:  refreshExpiry,
 	}
 }
 
@@ -47,7 +79,7 @@ func (s *userService) Register(req *entity.RegisterRequest) (*entity.User, error
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
+	hashedPassword, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +87,7 @@ func (s *userService) Register(req *entity.RegisterRequest) (*entity.User, error
 	// Create user
 	user := &entity.User{
 		Email:    req.Email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 		Name:     req.Name,
 		Role:     entity.RoleUser,
 		IsActive: true,
@@ -84,7 +116,7 @@ func (s *userService) Login(req *entity.LoginRequest) (*entity.LoginResponse, er
 	}
 
 	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+	if err := s.hasher.Compare(user.Password, req.Password); err != nil {
 		return nil, errors.New("invalid email or password")
 	}
 
@@ -94,47 +126,254 @@ func (s *userService) Login(req *entity.LoginRequest) (*entity.LoginResponse, er
 		return nil, err
 	}
 
+	refreshToken, stored, err := s.newRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+
 	return &entity.LoginResponse{
-		Token: token,
-		User:  user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user,
 	}, nil
 }
 
-func (s *userService) GetProfile(userID string) (*entity.User, error) {
-	return s.userRepo.GetByID(userID)
+// RefreshToken exchanges a refresh token for a new access token and a new refresh
+// token, revoking the one presented. A token that was already used means it leaked,
+// so all of the user's refresh tokens are revoked and they must log in again.
+func (s *userService) RefreshToken(token string) (*entity.LoginResponse, error) {
+	if token == "" {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	current, err := s.userRepo.GetRefreshTokenByHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired refresh token")
+		}
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		if current.ReplacedBy != "" {
+			s.revokeReusedRefreshToken(current)
+		}
+		return nil, errors.New("invalid or expired refresh token")
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	user, err := s.userRepo.GetByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired refresh token")
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.New("account is deactivated")
+	}
+
+	refreshToken, next, err := s.newRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.userRepo.RotateRefreshToken(current, next)
+	if err != nil {
+		return nil, err
+	}
+	// Another request used the same token first
+	if !rotated {
+		s.revokeReusedRefreshToken(current)
+		return nil, errors.New("invalid or expired refresh token")
+	}
+
+	accessToken, err := s.GenerateJWT(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		User:         user,
+	}, nil
+}
+
+// newRefreshToken returns a random token and its stored form, which keeps only the hash
+func (s *userService) newRefreshToken(userID string) (string, *entity.RefreshToken, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &entity.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.refreshExpiry),
+	}, nil
 }
 
-func (s *userService) UpdateProfile(userID string, updateData *entity.User) (*entity.User, error) {
+// revokeReusedRefreshToken ends every session of a user whose used refresh token was
+// presented again. The request is rejected either way, so a failure is only logged.
+func (s *userService) revokeReusedRefreshToken(token *entity.RefreshToken) {
+	log.Printf("audit: reused refresh token %s for user %s, revoking all refresh tokens", token.ID, token.UserID)
+	if err := s.userRepo.RevokeRefreshTokens(token.UserID); err != nil {
+		log.Printf("failed to revoke refresh tokens of user %s: %v", token.UserID, err)
+	}
+}
+
+func (s *userService) GetProfile(userID string) (*entity.User, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update only allowed fields
-	if updateData.Name != "" {
-		user.Name = updateData.Name
+	count, err := s.userRepo.CountUpcomingTickets(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.UpcomingTicketsCount = &count
+
+	return user, nil
+}
+
+func (s *userService) UpdateProfile(userID string, updateData *entity.UpdateProfileRequest) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
 	}
+
+	// A new email only takes effect once confirmed from that address
 	if updateData.Email != "" && updateData.Email != user.Email {
-		// Check if new email is already taken
-		existingUser, err := s.userRepo.GetByEmail(updateData.Email)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if user, err = s.RequestEmailChange(userID, updateData.Email); err != nil {
 			return nil, err
 		}
-		if existingUser != nil {
-			return nil, errors.New("email already taken")
+	}
+
+	if updateData.Name != "" {
+		user.Name = updateData.Name
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RequestEmailChange stores newEmail as pending and emails a confirmation link to it.
+// The account keeps its current email until ConfirmEmailChange is called with the token.
+func (s *userService) RequestEmailChange(userID, newEmail string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Display-name forms such as "Bob <bob@x.com>" parse too, but must not become the stored email
+	address, err := mail.ParseAddress(newEmail)
+	if err != nil || address.Address != newEmail {
+		return nil, errors.New("invalid email address")
+	}
+	if newEmail == user.Email {
+		return nil, errors.New("email is unchanged")
+	}
+
+	if err := s.checkEmailAvailable(newEmail); err != nil {
+		return nil, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.emailChange.TokenTTL)
+	user.PendingEmail = newEmail
+	user.EmailChangeToken = hashToken(token)
+	user.EmailChangeExpiresAt = &expiresAt
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	subject, body, err := s.emails.Render(EmailTemplateEmailChange, EmailData{
+		User:      user,
+		Link:      s.emailChange.ConfirmURL + token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifier.Send(newEmail, subject, body); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ConfirmEmailChange swaps in the pending email for the account owning token
+func (s *userService) ConfirmEmailChange(token string) (*entity.User, error) {
+	if token == "" {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	user, err := s.userRepo.GetByEmailChangeToken(hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired token")
 		}
-		user.Email = updateData.Email
+		return nil, err
+	}
+
+	if user.PendingEmail == "" || user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) {
+		return nil, errors.New("invalid or expired token")
 	}
 
+	// The address may have been registered since the change was requested
+	if err := s.checkEmailAvailable(user.PendingEmail); err != nil {
+		return nil, err
+	}
+
+	previousEmail := user.Email
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailChangeToken = ""
+	user.EmailChangeExpiresAt = nil
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, err
 	}
 
+	// Let the previous address know; the change stands even if this notice fails
+	if subject, body, err := s.emails.Render(EmailTemplateEmailChanged, EmailData{User: user}); err == nil {
+		_ = s.notifier.Send(previousEmail, subject, body)
+	}
+
 	return user, nil
 }
 
-func (s *userService) GetAllUsers(pagination *entity.Pagination, search *entity.Search) ([]entity.User, *entity.PaginationMeta, error) {
-	users, total, err := s.userRepo.GetAll(pagination, search)
+func (s *userService) checkEmailAvailable(email string) error {
+	existingUser, err := s.userRepo.GetByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existingUser != nil {
+		return errors.New("email already taken")
+	}
+	return nil
+}
+
+// hashToken stores tokens as SHA-256 digests so a database leak does not expose usable links
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *userService) GetAllUsers(pagination *entity.Pagination, search *entity.Search, filter *entity.UserFilter) ([]entity.User, *entity.PaginationMeta, error) {
+	users, total, err := s.userRepo.GetAll(pagination, search, filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -164,11 +403,17 @@ func (s *userService) DeleteUser(userID string) error {
 }
 
 func (s *userService) GenerateJWT(user *entity.User) (string, error) {
+	// Privileged sessions may be configured to expire sooner
+	expiry := s.jwtExpiry
+	if user.IsAdmin() {
+		expiry = s.jwtAdminExpiry
+	}
+
 	claims := jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
 		"role":    user.Role,
-		"exp":     time.Now().Add(s.jwtExpiry).Unix(),
+		"exp":     time.Now().Add(expiry).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 